@@ -0,0 +1,234 @@
+package main
+
+import (
+	"enspired/src"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+)
+
+// Version is set at build time, see the build target of the Makefile.
+var Version = "dev"
+
+// Exit codes, one per class of failure, so shell pipelines can tell them apart.
+const (
+	exitOK = 0
+	// the plan parsed, but there's something wrong with it: rooms that never close, a plan that's not formatted
+	exitInvalid = 1
+	// the command line doesn't make sense. The flag package exits with 2 too.
+	exitUsage = 2
+	// a file couldn't be read or written
+	exitIO = 3
+	// the plan couldn't be parsed
+	exitParse = 4
+)
+
+// severity ranks the exit codes of the commands going through many files, from the best to the worst:
+// they exit with the worst status any of the files got. A plan that parsed, even with problems, beats a file
+// that couldn't be read, and that beats a plan that doesn't parse: the file may well be fine once it can be read.
+// Usage errors stop a command before it gets to the files, so they aren't ranked.
+var severity = []int{exitOK, exitInvalid, exitIO, exitParse}
+
+// worse is the worse of two exit codes, by their severity.
+func worse(status, other int) int {
+	if slices.Index(severity, other) > slices.Index(severity, status) {
+		return other
+	}
+	return status
+}
+
+// stdinName is what the file named "-", the standard input, is called in messages.
+const stdinName = "<stdin>"
+
+// cli is what the commands run with: the standard streams, swapped for buffers in the tests, and the global flags.
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	// quiet keeps everything off the standard error: the exit code is all there is to go by
+	quiet bool
+	// verbose tells more on the standard error: what was found in each plan, the whole chain of each error
+	verbose bool
+	// trace logs every decision of the parser to the standard error
+	trace bool
+	// categories, if set, categorize the rooms of every plan read
+	categories *src.Categories
+}
+
+// report tells the user something on the standard error, unless --quiet.
+func (c *cli) report(format string, args ...any) {
+	if c.quiet {
+		return
+	}
+	fmt.Fprintf(c.stderr, format+"\n", args...)
+}
+
+// note tells the user something only worth knowing with --verbose.
+func (c *cli) note(format string, args ...any) {
+	if !c.verbose || c.quiet {
+		return
+	}
+	fmt.Fprintf(c.stderr, format+"\n", args...)
+}
+
+// flagSet makes the flag set of a command. Its usage is "enspired " + usage, then the summary, then the flags.
+func (c *cli) flagSet(name, usage, summary string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: enspired %s\n\n%s\n", usage, summary)
+		hasFlags := false
+		flags.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			fmt.Fprintln(flags.Output(), "\nFlags:")
+			flags.PrintDefaults()
+		}
+	}
+	return flags
+}
+
+// parseFlags parses the arguments of a command. When there's no point going on, ok is false
+// and status is what to exit with: exitOK if -h only asked for the usage, exitUsage if the flags are wrong.
+func parseFlags(flags *flag.FlagSet, args []string) (status int, ok bool) {
+	err := flags.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK, false
+	}
+	if err != nil {
+		return exitUsage, false
+	}
+	return exitOK, true
+}
+
+// usageError shows the usage of a command whose arguments are wrong, and returns exitUsage.
+func usageError(flags *flag.FlagSet) int {
+	flags.Usage()
+	return exitUsage
+}
+
+// open opens the named file, or the standard input if the name is "-".
+func (c *cli) open(name string) (io.ReadCloser, error) {
+	if name == "-" {
+		return io.NopCloser(c.stdin), nil
+	}
+	return os.Open(name)
+}
+
+// displayName is how the named file is called in messages.
+func displayName(name string) string {
+	if name == "-" {
+		return stdinName
+	}
+	return name
+}
+
+// readFile reads the whole named file, or the standard input if the name is "-", reporting why it couldn't if so.
+// The exit code is exitOK if it could.
+func (c *cli) readFile(name string) ([]byte, int) {
+	file, err := c.open(name)
+	if err != nil {
+		c.report("Could not open file %s: %v", displayName(name), err)
+		return nil, exitIO
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		c.report("Could not read file %s: %v", displayName(name), err)
+		return nil, exitIO
+	}
+	return content, exitOK
+}
+
+// newParser makes a parser, tracing its decisions if asked to.
+func (c *cli) newParser() *src.FlatParser {
+	parser := src.NewRoomParser()
+	if c.trace {
+		parser.Logger = slog.New(slog.NewTextHandler(c.stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	}
+	return parser
+}
+
+// readPlan parses the plan in the named file, or the standard input if the name is "-",
+// reporting why it couldn't if so. The exit code is exitOK if it could.
+func (c *cli) readPlan(name string) (*src.Plan, int) {
+	return c.readPlanWith(c.newParser(), name)
+}
+
+// readPlanWith is readPlan, with the given parser.
+func (c *cli) readPlanWith(parser *src.FlatParser, name string) (*src.Plan, int) {
+	file, err := c.open(name)
+	if err != nil {
+		c.report("Could not open file %s: %v", displayName(name), err)
+		return nil, exitIO
+	}
+	defer file.Close()
+
+	plan, err := parser.ReadPlan(file)
+	if err != nil {
+		return nil, c.reportParseError(name, err)
+	}
+	c.note("%s: %d lines parsed, rooms found: %d", displayName(name), len(plan.Lines), len(plan.Rooms))
+	if c.categories != nil {
+		plan.Categorize(c.categories)
+	}
+	return plan, exitOK
+}
+
+// readCategories reads the room categories every plan read gets categorized with.
+// The exit code is exitOK if it could.
+func (c *cli) readCategories(name string) int {
+	file, err := c.open(name)
+	if err != nil {
+		c.report("Could not open file %s: %v", displayName(name), err)
+		return exitIO
+	}
+	defer file.Close()
+	if c.categories, err = src.ReadCategories(file); err != nil {
+		c.report("Error processing %s: %v", displayName(name), err)
+		return exitParse
+	}
+	return exitOK
+}
+
+// reportParseError reports an error ReadPlan returned for the named file the way compilers do, file:line:column,
+// and tells whether it was the plan's fault (exitParse) or the file's (exitIO).
+func (c *cli) reportParseError(name string, err error) int {
+	var parseErr *src.ParseError
+	if !errors.As(err, &parseErr) {
+		c.report("Could not read file %s: %v", displayName(name), err)
+		return exitIO
+	}
+	if c.verbose {
+		c.report("%s:%d:%d: %v", displayName(name), parseErr.Line, parseErr.Column, err)
+	} else {
+		c.report("%s:%d:%d: %s", displayName(name), parseErr.Line, parseErr.Column, parseErr.Message)
+	}
+	return exitParse
+}
+
+// reportProblems reports what's wrong with a plan that parsed, and returns exitInvalid if there's anything.
+func (c *cli) reportProblems(name string, plan *src.Plan) int {
+	problems := plan.Problems()
+	for _, problem := range problems {
+		c.report("%s:%d:%d: %s", displayName(name), problem.Line, problem.Column, problem.Message)
+	}
+	if len(problems) > 0 {
+		return exitInvalid
+	}
+	return exitOK
+}
+
+// isTerminal tells whether colours can be used on w: it has to be a terminal, and NO_COLOR must not be set.
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
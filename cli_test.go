@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writePlan writes a plan into a file of its own, and returns the file's name.
+func writePlan(t *testing.T, content string) string {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "plan.txt")
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+// runCLI runs the tool with args, and returns what it wrote on the standard output and error, and the exit code.
+func runCLI(args ...string) (stdout, stderr string, status int) {
+	var out, errOut bytes.Buffer
+	c := &cli{stdin: strings.NewReader(""), stdout: &out, stderr: &errOut}
+	status = c.run(args)
+	return out.String(), errOut.String(), status
+}
+
+func TestCLI_ExitCodes(t *testing.T) {
+	closed := writePlan(t, "+-----------+\n| (kitchen) |\n|  W     W  |\n+-----------+\n")
+	open := writePlan(t, "+-----------+\n| (kitchen) |\n|  W     W  |\n")
+	broken := writePlan(t, "+-----------+\n| (kitchen) |\n|  W  X  W  |\n+-----------+\n")
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "good plan", args: []string{closed}, want: exitOK},
+		{name: "room never closed", args: []string{open}, want: exitInvalid},
+		{name: "no file", args: nil, want: exitUsage},
+		{name: "unknown flag", args: []string{"--no-such-flag", closed}, want: exitUsage},
+		{name: "missing file", args: []string{filepath.Join(t.TempDir(), "missing.txt")}, want: exitIO},
+		{name: "strange character", args: []string{broken}, want: exitParse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, stderr, got := runCLI(tt.args...); got != tt.want {
+				t.Errorf("run(%q) = %d, want %d; stderr:\n%s", tt.args, got, tt.want, stderr)
+			}
+		})
+	}
+}
+
+func TestCLI_ErrorsOnStderr(t *testing.T) {
+	broken := writePlan(t, "+-----------+\n| (kitchen) |\n|  W  X  W  |\n+-----------+\n")
+
+	stdout, stderr, _ := runCLI(broken)
+	if stdout != "" {
+		t.Errorf("stdout = %q, want nothing", stdout)
+	}
+	if want := broken + ":3:7: strange character encountered: X\n"; stderr != want {
+		t.Errorf("stderr = %q, want %q", stderr, want)
+	}
+}
+
+func TestCLI_Quiet(t *testing.T) {
+	broken := writePlan(t, "+-----------+\n| (kitchen) |\n|  W  X  W  |\n+-----------+\n")
+
+	_, stderr, status := runCLI("--quiet", broken)
+	if stderr != "" {
+		t.Errorf("stderr = %q, want nothing", stderr)
+	}
+	if status != exitParse {
+		t.Errorf("status = %d, want %d", status, exitParse)
+	}
+}
+
+func TestCLI_Verbose(t *testing.T) {
+	closed := writePlan(t, "+-----------+\n| (kitchen) |\n|  W     W  |\n+-----------+\n")
+	broken := writePlan(t, "+-----------+\n| (kitchen) |\n|  W  X  W  |\n+-----------+\n")
+
+	if _, stderr, _ := runCLI("--verbose", closed); stderr != closed+": 4 lines parsed, rooms found: 1\n" {
+		t.Errorf("stderr for a good plan = %q", stderr)
+	}
+	_, stderr, _ := runCLI("--verbose", broken)
+	if !strings.Contains(stderr, "error parsing segment: strange character encountered: X") {
+		t.Errorf("stderr for a broken plan = %q, want the whole chain of the error", stderr)
+	}
+}
+
+func TestCLI_Version(t *testing.T) {
+	stdout, stderr, status := runCLI("--version")
+	if stdout != "enspired dev\n" || stderr != "" || status != exitOK {
+		t.Errorf("run(--version) = %q, %q, %d", stdout, stderr, status)
+	}
+}
+
+func TestWorse(t *testing.T) {
+	tests := []struct {
+		status, other, want int
+	}{
+		{exitOK, exitInvalid, exitInvalid},
+		{exitInvalid, exitOK, exitInvalid},
+		{exitInvalid, exitIO, exitIO},
+		{exitIO, exitParse, exitParse},
+		{exitParse, exitIO, exitParse},
+		{exitParse, exitInvalid, exitParse},
+	}
+	for _, tt := range tests {
+		if got := worse(tt.status, tt.other); got != tt.want {
+			t.Errorf("worse(%d, %d) = %d, want %d", tt.status, tt.other, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,646 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"enspired/src"
+	"enspired/src/lsp"
+	"enspired/src/planpb"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/term"
+	"google.golang.org/grpc"
+)
+
+// fileProblems is what the validate command tells about a file in JSON.
+type fileProblems struct {
+	File     string            `json:"file"`
+	Valid    bool              `json:"valid"`
+	Problems []*src.ParseError `json:"problems"`
+	// Findings are where the plan breaks the placement rules, if validate was given any.
+	Findings []*src.Finding `json:"findings,omitempty"`
+}
+
+// validate is the validate command: it lists what's wrong with each plan, the error the parser gave up on
+// or the rooms that never close, and where it breaks the placement rules, on the standard output.
+// Valid plans get no output.
+func (c *cli) validate(args []string) int {
+	flags := c.flagSet("validate", "validate [-json] [-rules file] file...",
+		"Lists what's wrong with each plan: the error the parser gave up on, the rooms that never close,\n"+
+			"and, given placement rules, the chairs that make no sense where they are.\n"+
+			"Exits with 4 if a plan couldn't be parsed, or else with 1 if there's anything wrong with one.\n"+
+			"Rules with the warning severity don't count.")
+	asJSON := flags.Bool("json", false, "print the problems of each file as JSON")
+	rulesName := flags.String("rules", "", "JSON file with the placement rules to check the rooms against")
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	if flags.NArg() == 0 {
+		return usageError(flags)
+	}
+
+	var rules *src.Rules
+	if *rulesName != "" {
+		file, err := c.open(*rulesName)
+		if err != nil {
+			c.report("Could not open file %s: %v", displayName(*rulesName), err)
+			return exitIO
+		}
+		rules, err = src.ReadRules(file)
+		file.Close()
+		if err != nil {
+			c.report("Error processing %s: %v", displayName(*rulesName), err)
+			return exitParse
+		}
+		if rules.UsesCategories() && c.categories == nil {
+			c.report("The rules pick rooms by category: the categories are needed too, see enspired -categories")
+			return exitUsage
+		}
+	}
+
+	status := exitOK
+	results := []fileProblems{}
+	for _, name := range flags.Args() {
+		file, err := c.open(name)
+		if err != nil {
+			c.report("Could not open file %s: %v", displayName(name), err)
+			status = worse(status, exitIO)
+			continue
+		}
+		plan, err := c.newParser().ReadPlan(file)
+		file.Close()
+
+		var problems []*src.ParseError
+		var findings []*src.Finding
+		var parseErr *src.ParseError
+		switch {
+		case errors.As(err, &parseErr):
+			problems = []*src.ParseError{parseErr}
+			status = worse(status, exitParse)
+		case err != nil:
+			c.report("Could not read file %s: %v", displayName(name), err)
+			status = worse(status, exitIO)
+			continue
+		default:
+			problems = plan.Problems()
+			if rules != nil {
+				if c.categories != nil {
+					plan.Categorize(c.categories)
+				}
+				findings = rules.Check(plan)
+			}
+		}
+		valid := len(problems) == 0
+		for _, finding := range findings {
+			valid = valid && finding.Severity != src.SeverityError
+		}
+		if !valid {
+			status = worse(status, exitInvalid)
+		}
+
+		c.note("%s: %d problems, %d rules broken", displayName(name), len(problems), len(findings))
+		if *asJSON {
+			results = append(results, fileProblems{File: displayName(name), Valid: valid, Problems: problems, Findings: findings})
+			continue
+		}
+		for _, problem := range problems {
+			fmt.Fprintf(c.stdout, "%s:%d:%d: %s\n", displayName(name), problem.Line, problem.Column, problem.Message)
+		}
+		for _, finding := range findings {
+			fmt.Fprintf(c.stdout, "%s:%d:%d: %s: %s\n", displayName(name), finding.Line, finding.Column, finding.Severity, finding.Message)
+		}
+	}
+
+	if *asJSON {
+		if err := c.writeJSON(results); err != nil {
+			c.report("Could not write the problems: %v", err)
+			return exitIO
+		}
+	}
+	return status
+}
+
+// render is the render command: it draws a plan as an SVG image on the standard output.
+func (c *cli) render(args []string) int {
+	flags := c.flagSet("render", "render file", "Draws the plan as an SVG image: the rooms coloured, the chairs as dots.")
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	if flags.NArg() != 1 {
+		return usageError(flags)
+	}
+
+	plan, status := c.readPlan(flags.Arg(0))
+	if status != exitOK {
+		return status
+	}
+	if err := plan.RenderSVG(c.stdout); err != nil {
+		c.report("Could not write the image: %v", err)
+		return exitIO
+	}
+	return exitOK
+}
+
+// formatFiles is the fmt command: it prints the canonical form of each plan,
+// or, with -check, lists the plans that are not formatted and exits with 1, like gofmt -l.
+func (c *cli) formatFiles(args []string) int {
+	flags := c.flagSet("fmt", "fmt [-check] file...",
+		"Prints the plans in their canonical form: trimmed lines, + at every wall junction, titles centred in their rooms.")
+	check := flags.Bool("check", false, "don't print the formatted plans, list the ones that are not formatted")
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	if flags.NArg() == 0 {
+		return usageError(flags)
+	}
+
+	status := exitOK
+	for _, name := range flags.Args() {
+		content, readStatus := c.readFile(name)
+		if readStatus != exitOK {
+			status = worse(status, readStatus)
+			continue
+		}
+		plan, err := c.newParser().ReadPlan(bytes.NewReader(content))
+		if err != nil {
+			status = worse(status, c.reportParseError(name, err))
+			continue
+		}
+		formatted := plan.Format()
+		if !*check {
+			if _, err := c.stdout.Write(formatted); err != nil {
+				c.report("Could not write the formatted plan: %v", err)
+				return exitIO
+			}
+			continue
+		}
+		if !bytes.Equal(content, formatted) {
+			fmt.Fprintln(c.stdout, displayName(name))
+			status = worse(status, exitInvalid)
+		}
+	}
+	return status
+}
+
+// diffFiles is the diff command: it reports the rooms and chairs that changed between two versions of a plan.
+func (c *cli) diffFiles(args []string) int {
+	flags := c.flagSet("diff", "diff [-json] [-old-result] old new",
+		"Lists the rooms added, removed, renamed or with other chairs in the new version of the plan, and the change in the totals.")
+	asJSON := flags.Bool("json", false, "print the changes as JSON")
+	oldResult := flags.Bool("old-result", false,
+		"the old version is a result file of the old system (the output of parse) rather than a plan.\n"+
+			"Its rooms are matched by name only, so unnamed ones come out as removed and added")
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	if flags.NArg() != 2 {
+		return usageError(flags)
+	}
+
+	var plans [2]*src.Plan
+	for i, name := range flags.Args() {
+		if i == 0 && *oldResult {
+			content, status := c.readFile(name)
+			if status != exitOK {
+				return status
+			}
+			result, err := src.ReadResult(bytes.NewReader(content))
+			if err != nil {
+				c.report("Error processing %s: %v", displayName(name), err)
+				return exitParse
+			}
+			plans[i] = result.Plan()
+			continue
+		}
+		var status int
+		if plans[i], status = c.readPlan(name); status != exitOK {
+			return status
+		}
+	}
+
+	diff := src.DiffPlans(plans[0], plans[1])
+	var err error
+	if *asJSON {
+		err = c.writeJSON(diff)
+	} else {
+		_, err = fmt.Fprintln(c.stdout, diff)
+	}
+	if err != nil {
+		c.report("Could not write the changes: %v", err)
+		return exitIO
+	}
+	return exitOK
+}
+
+// stats is the stats command: it sums up the size of a plan, its rooms and their chairs.
+func (c *cli) stats(args []string) int {
+	flags := c.flagSet("stats", "stats [-json] [-max-density n] file",
+		"Sums up the plan: its size, the floor area and chairs of the whole plan and of each room,\n"+
+			"and how crowded each room is: its free cells, and its chairs per 100 cells.")
+	asJSON := flags.Bool("json", false, "print the stats as JSON")
+	maxDensity := flags.Float64("max-density", src.DefaultMaxDensity, "flag the rooms with more chairs than this per 100 cells as overcrowded")
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	if flags.NArg() != 1 {
+		return usageError(flags)
+	}
+	if *maxDensity < 0 {
+		c.report("The density can't be negative: %g", *maxDensity)
+		return exitUsage
+	}
+
+	plan, status := c.readPlan(flags.Arg(0))
+	if status != exitOK {
+		return status
+	}
+	stats := plan.Stats()
+	stats.FlagOvercrowded(*maxDensity)
+	var err error
+	if *asJSON {
+		err = c.writeJSON(stats)
+	} else {
+		_, err = fmt.Fprint(c.stdout, stats)
+	}
+	if err != nil {
+		c.report("Could not write the stats: %v", err)
+		return exitIO
+	}
+	return exitOK
+}
+
+// exportRecords is the export command: it writes the chairs of each room as the fixed-width records
+// the old system's batch importer reads.
+func (c *cli) exportRecords(args []string) int {
+	flags := c.flagSet("export", "export [-layout file] file",
+		"Writes the chairs of each room as fixed-width records, for the old system's batch importer:\n"+
+			"the room's name padded or truncated, then a zero-padded count per chair type, in catalog order.")
+	layoutName := flags.String("layout", "",
+		`JSON file with the record layout: {"name_width": 20, "count_width": 4, "total": "TOTAL"}, total being optional`)
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	if flags.NArg() != 1 {
+		return usageError(flags)
+	}
+
+	layout := &src.DefaultRecordLayout
+	if *layoutName != "" {
+		file, err := c.open(*layoutName)
+		if err != nil {
+			c.report("Could not open file %s: %v", displayName(*layoutName), err)
+			return exitIO
+		}
+		layout, err = src.ReadRecordLayout(file)
+		file.Close()
+		if err != nil {
+			c.report("Error processing %s: %v", displayName(*layoutName), err)
+			return exitParse
+		}
+	}
+
+	plan, status := c.readPlan(flags.Arg(0))
+	if status != exitOK {
+		return status
+	}
+	if err := src.WriteRecords(c.stdout, plan.Result(), layout); err != nil {
+		c.report("Could not write the records: %v", err)
+		return exitIO
+	}
+	return c.reportProblems(flags.Arg(0), plan)
+}
+
+// whereIs is the where command: it tells which room the cell at a given line and column belongs to,
+// or whether it's a wall or outside the rooms.
+func (c *cli) whereIs(args []string) int {
+	flags := c.flagSet("where", "where file line column",
+		"Tells which room the cell at the line and column belongs to, or whether it's a wall or outside the rooms.\n"+
+			"Lines and columns start at 1, like in an editor.")
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	if flags.NArg() != 3 {
+		return usageError(flags)
+	}
+	line, err := strconv.Atoi(flags.Arg(1))
+	if err != nil {
+		return usageError(flags)
+	}
+	column, err := strconv.Atoi(flags.Arg(2))
+	if err != nil {
+		return usageError(flags)
+	}
+
+	plan, status := c.readPlan(flags.Arg(0))
+	if status != exitOK {
+		return status
+	}
+
+	pos := src.Position{Line: line, Column: column}
+	room, kind := plan.RoomAt(pos)
+	if kind != src.Floor {
+		fmt.Fprintf(c.stdout, "%s: %s\n", pos, kind)
+		return exitOK
+	}
+	name := room.Name
+	if name == "" {
+		name = "(no name)"
+	}
+	fmt.Fprintf(c.stdout, "%s: %s\n", pos, name)
+	return exitOK
+}
+
+// productionOrder is the order command: it adds up the chairs of many apartment plans
+// and writes the production order for them.
+// Each plan is an apartment, named after its file; the directory the file is in is the building.
+func (c *cli) productionOrder(args []string) int {
+	flags := c.flagSet("order", "order [-config file] [-format text|json|csv] plan...",
+		"Adds up the chairs of the apartment plans and writes the production order for them, spares and batches included.\n"+
+			"Each plan is an apartment named after its file, in the building named after the file's directory.\n"+
+			"Plans with apartment labels are as many apartments, the chairs outside them going to the building's common areas.")
+	configName := flags.String("config", "", "JSON file with the spare percentages and batch sizes of each chair type")
+	format := flags.String("format", "text", "output format: text, json or csv")
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	if flags.NArg() == 0 || *format != "text" && *format != "json" && *format != "csv" {
+		return usageError(flags)
+	}
+
+	config := &src.OrderConfig{}
+	if *configName != "" {
+		file, err := c.open(*configName)
+		if err != nil {
+			c.report("Could not open file %s: %v", displayName(*configName), err)
+			return exitIO
+		}
+		config, err = src.ReadOrderConfig(file)
+		file.Close()
+		if err != nil {
+			c.report("Error processing %s: %v", displayName(*configName), err)
+			return exitParse
+		}
+	}
+
+	var apartments []src.ApartmentChairs
+	for _, name := range flags.Args() {
+		plan, status := c.readPlan(name)
+		if status != exitOK {
+			return status
+		}
+		building := filepath.Base(filepath.Dir(name))
+		rest, restCount := plan.Totals(), 0
+		planApartments := plan.Apartments()
+		for _, apartment := range planApartments {
+			apartments = append(apartments, src.ApartmentChairs{Building: building, Apartment: apartment.Name, Chairs: apartment.Totals()})
+			for chairType, count := range apartment.Totals() {
+				rest[chairType] -= count
+			}
+		}
+		for _, count := range rest {
+			restCount += count
+		}
+		switch {
+		case len(planApartments) == 0:
+			apartments = append(apartments, src.ApartmentChairs{
+				Building:  building,
+				Apartment: strings.TrimSuffix(filepath.Base(name), filepath.Ext(name)),
+				Chairs:    rest,
+			})
+		case restCount > 0:
+			apartments = append(apartments, src.ApartmentChairs{Building: building, Chairs: rest})
+		}
+	}
+
+	order := src.NewProductionOrder(apartments, config)
+	var err error
+	switch *format {
+	case "text":
+		_, err = fmt.Fprintln(c.stdout, order)
+	case "json":
+		err = c.writeJSON(order)
+	case "csv":
+		err = order.WriteCSV(c.stdout)
+	}
+	if err != nil {
+		c.report("Could not write the order: %v", err)
+		return exitIO
+	}
+	return exitOK
+}
+
+// reconcileStock is the stock command: it adds up the chairs of the plans
+// and tells how many of each type can be drawn from stock and how many must be produced.
+func (c *cli) reconcileStock(args []string) int {
+	flags := c.flagSet("stock", "stock [-json] stock.csv plan...",
+		"Adds up the chairs of the plans, and tells how many of each type can be drawn from stock and how many must be produced.\n"+
+			"The stock file has a header, then a row per chair type: type,on_hand,reserved.")
+	asJSON := flags.Bool("json", false, "print the reconciliation as JSON")
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	if flags.NArg() < 2 {
+		return usageError(flags)
+	}
+
+	stockFile, err := c.open(flags.Arg(0))
+	if err != nil {
+		c.report("Could not open file %s: %v", displayName(flags.Arg(0)), err)
+		return exitIO
+	}
+	stock, err := src.ReadStock(stockFile)
+	stockFile.Close()
+	if err != nil {
+		c.report("Error processing %s: %v", displayName(flags.Arg(0)), err)
+		return exitParse
+	}
+
+	needed := map[rune]int{}
+	for _, name := range flags.Args()[1:] {
+		plan, status := c.readPlan(name)
+		if status != exitOK {
+			return status
+		}
+		for chairType, count := range plan.Totals() {
+			needed[chairType] += count
+		}
+	}
+
+	reconciliation := src.Reconcile(needed, stock)
+	if *asJSON {
+		err = c.writeJSON(reconciliation)
+	} else {
+		_, err = fmt.Fprintln(c.stdout, reconciliation)
+	}
+	if err != nil {
+		c.report("Could not write the reconciliation: %v", err)
+		return exitIO
+	}
+	return exitOK
+}
+
+// serve is the serve command: it runs the HTTP API, and the gRPC one if asked to, until it gets SIGINT or SIGTERM,
+// then gives the requests in flight some time to finish.
+func (c *cli) serve(args []string) int {
+	flags := c.flagSet("serve", "serve [-addr host:port] [-grpc-addr host:port] [-max-plan-size bytes]",
+		"Runs the HTTP API, and the gRPC one if given an address for it, until interrupted.")
+	addr := flags.String("addr", ":8080", "address to listen on")
+	grpcAddr := flags.String("grpc-addr", "", "address to serve the gRPC API on, if any")
+	maxPlanSize := flags.Int64("max-plan-size", src.DefaultMaxPlanSize, "biggest plan accepted, in bytes")
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	if flags.NArg() != 0 {
+		return usageError(flags)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           src.NewServer(*maxPlanSize),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	failed := make(chan error, 2)
+	go func() {
+		c.report("Listening on %s", *addr)
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			failed <- err
+		}
+	}()
+
+	grpcServer := grpc.NewServer(grpc.MaxRecvMsgSize(int(*maxPlanSize)))
+	planpb.RegisterPlanParserServer(grpcServer, &planpb.Service{})
+	if *grpcAddr != "" {
+		listener, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			c.report("Could not listen on %s: %v", *grpcAddr, err)
+			return exitIO
+		}
+		go func() {
+			c.report("Serving gRPC on %s", *grpcAddr)
+			if err := grpcServer.Serve(listener); err != nil {
+				failed <- err
+			}
+		}()
+	}
+
+	select {
+	case err := <-failed:
+		c.report("Server failed: %v", err)
+		return exitIO
+	case <-ctx.Done():
+	}
+
+	c.report("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	go func() {
+		<-shutdownCtx.Done()
+		grpcServer.Stop()
+	}()
+	grpcServer.GracefulStop()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		c.report("Could not shut down gracefully: %v", err)
+		return exitIO
+	}
+	return exitOK
+}
+
+// languageServer is the lsp command: it speaks LSP with the editor over the standard input and output.
+func (c *cli) languageServer(args []string) int {
+	flags := c.flagSet("lsp", "lsp", "Runs the language server, speaking LSP over the standard input and output.")
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	if flags.NArg() != 0 {
+		return usageError(flags)
+	}
+	if err := lsp.Serve(c.stdin, c.stdout); err != nil {
+		c.report("Language server failed: %v", err)
+		return exitIO
+	}
+	return exitOK
+}
+
+// viewPlan is the view command: it shows the plan full screen, with a cursor to point at cells with
+// and a side panel telling about the room under it. It quits on q.
+func (c *cli) viewPlan(args []string) int {
+	flags := c.flagSet("view", "view file",
+		"Shows the plan full screen, with the room under the cursor highlighted and its chairs and size next to it.\n"+
+			"Arrows or hjkl move the cursor, tab jumps to the next room, q quits.")
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	if flags.NArg() != 1 || flags.Arg(0) == "-" {
+		return usageError(flags)
+	}
+	in, out := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(in) || !term.IsTerminal(out) {
+		c.report("The viewer needs a terminal")
+		return exitUsage
+	}
+
+	plan, status := c.readPlan(flags.Arg(0))
+	if status != exitOK {
+		return status
+	}
+
+	state, err := term.MakeRaw(in)
+	if err != nil {
+		c.report("Could not set up the terminal: %v", err)
+		return exitIO
+	}
+	// the alternate screen, without the terminal's own cursor, leaves the scrollback alone
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	err = runViewer(src.NewViewer(plan), out)
+	fmt.Print("\x1b[?25h\x1b[?1049l")
+	_ = term.Restore(in, state)
+
+	if err != nil {
+		c.report("Viewer failed: %v", err)
+		return exitIO
+	}
+	return exitOK
+}
+
+// runViewer draws the viewer, then redraws it after every key, until the key is one for quitting.
+// The terminal's size is asked for on every frame, so resizing the window just works.
+func runViewer(viewer *src.Viewer, out int) error {
+	key := make([]byte, 16)
+	for {
+		width, height, err := term.GetSize(out)
+		if err != nil || width == 0 || height == 0 {
+			width, height = 80, 24
+		}
+		if err := viewer.Render(os.Stdout, width, height); err != nil {
+			return err
+		}
+		n, err := os.Stdin.Read(key)
+		if err != nil {
+			return err
+		}
+		if viewer.HandleKeys(string(key[:n])) {
+			return nil
+		}
+	}
+}
+
+// writeJSON writes v to the standard output as indented JSON.
+func (c *cli) writeJSON(v any) error {
+	encoder := json.NewEncoder(c.stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
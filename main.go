@@ -1,31 +1,218 @@
 package main
 
 import (
-	"bufio"
-	"enspired/src"
+	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"slices"
 )
 
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Missing input file argument")
-		os.Exit(1)
+	c := &cli{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}
+	os.Exit(c.run(os.Args[1:]))
+}
+
+// command is a subcommand of the CLI.
+type command struct {
+	name    string
+	summary string
+	run     func(c *cli, args []string) int
+}
+
+// commands are listed in the usage in this order.
+var commands = []command{
+	{"parse", "count the chairs in each room of a plan", (*cli).parse},
+	{"validate", "list what's wrong with plans", (*cli).validate},
+	{"render", "draw a plan as an SVG image", (*cli).render},
+	{"fmt", "bring plans to their canonical form", (*cli).formatFiles},
+	{"diff", "list the rooms and chairs that changed between two versions of a plan", (*cli).diffFiles},
+	{"stats", "sum up the size of a plan and what's in it", (*cli).stats},
+	{"export", "write the chairs of each room as fixed-width records for the old system", (*cli).exportRecords},
+	{"where", "tell which room a cell of a plan belongs to", (*cli).whereIs},
+	{"order", "write the production order for apartment plans", (*cli).productionOrder},
+	{"stock", "reconcile plans with the chairs in stock", (*cli).reconcileStock},
+	{"view", "look around a plan in the terminal", (*cli).viewPlan},
+	{"serve", "run the HTTP and gRPC APIs", (*cli).serve},
+	{"lsp", "run the language server for editors", (*cli).languageServer},
+}
+
+// interactiveCommands talk to a terminal or a client through the standard output, or keep running:
+// what they print can't go to a file with -o.
+var interactiveCommands = map[string]bool{"view": true, "serve": true, "lsp": true}
+
+// categorizingCommands print the categories of the rooms, or use them: -categories is for them only.
+var categorizingCommands = map[string]bool{"parse": true, "validate": true, "stats": true}
+
+// run takes the global flags, then runs the command. Given a file instead of a command, it parses the file,
+// the way the tool always did. It returns the exit code.
+func (c *cli) run(args []string) int {
+	flags := c.flagSet("enspired", "", "")
+	showVersion := flags.Bool("version", false, "print the version and exit")
+	flags.BoolVar(&c.quiet, "quiet", false, "print nothing on the standard error, the exit code tells how it went")
+	flags.BoolVar(&c.verbose, "verbose", false, "tell what was found in each plan, and the whole story behind each error")
+	flags.BoolVar(&c.trace, "trace", false,
+		"log every decision of the parser to the standard error: line segments, the rooms they go to, opened and closed rooms")
+	debugOverlay := flags.Bool("debug-overlay", false, "same as parse -debug-overlay, for when no command is given")
+	categories := flags.String("categories", "",
+		"JSON file with the rules putting rooms in categories by name, for totals per category in parse -json and stats, and for validate -rules.\n"+
+			"Not for the other commands, which have no categories")
+	output := flags.String("o", "", "write what the command prints to this file instead of the standard output.\n"+
+		"The file is only replaced once the command is done, and left as it was if it fails. Not for view, serve and lsp")
+	flags.Usage = func() { c.usage(flags.Output(), flags.PrintDefaults) }
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
 	}
 
-	file, err := os.Open(os.Args[1])
-	if err != nil {
-		fmt.Printf("Could not open file %s: %v", os.Args[1], err)
-		os.Exit(1)
+	if *showVersion {
+		fmt.Fprintln(c.stdout, "enspired", Version)
+		return exitOK
+	}
+	if flags.NArg() == 0 {
+		c.report("Missing command or input file")
+		return usageError(flags)
+	}
+	if *categories != "" {
+		name := flags.Arg(0)
+		if slices.ContainsFunc(commands, func(cmd command) bool { return cmd.name == name }) && !categorizingCommands[name] {
+			c.report("-categories can't be used with %s, which has no categories", name)
+			return exitUsage
+		}
+		if status := c.readCategories(*categories); status != exitOK {
+			return status
+		}
 	}
-	defer file.Close()
 
-	parser := src.NewRoomParser()
-	err = parser.IngestAllFromReader(bufio.NewReader(file))
+	if *output == "" || *output == "-" {
+		return c.runCommand(flags, *debugOverlay)
+	}
+	if interactiveCommands[flags.Arg(0)] {
+		c.report("-o can't be used with %s, which talks through the standard output", flags.Arg(0))
+		return exitUsage
+	}
+	file, err := createAtomic(*output)
 	if err != nil {
-		fmt.Printf("Error processing input: %v", err)
-		return
+		c.report("Could not create file %s: %v", *output, err)
+		return exitIO
+	}
+	stdout := c.stdout
+	c.stdout = file
+	status := c.runCommand(flags, *debugOverlay)
+	c.stdout = stdout
+	// an invalid plan still has its output in full, a failed command may not
+	if status != exitOK && status != exitInvalid {
+		file.abort()
+		return status
 	}
+	if err := file.commit(); err != nil {
+		c.report("Could not write file %s: %v", *output, err)
+		return exitIO
+	}
+	c.note("%s written", *output)
+	return status
+}
+
+// runCommand runs the command the global flags are followed by, or parses the file they are followed by.
+func (c *cli) runCommand(flags *flag.FlagSet, debugOverlay bool) int {
+	name, commandArgs := flags.Arg(0), flags.Args()[1:]
+	if name == "help" {
+		return c.help(commandArgs, flags.PrintDefaults)
+	}
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd.run(c, commandArgs)
+		}
+	}
+
+	parseArgs := flags.Args()
+	if debugOverlay {
+		parseArgs = append([]string{"-debug-overlay"}, parseArgs...)
+	}
+	return c.parse(parseArgs)
+}
 
-	fmt.Printf("%s\n", parser)
+// usage shows how to run the tool, and lists the commands.
+func (c *cli) usage(w io.Writer, printFlags func()) {
+	fmt.Fprint(w, `Usage: enspired [flags] command [arguments]
+       enspired [flags] file    (same as enspired parse file)
+
+A file named - is the standard input.
+
+Commands:
+`)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-9s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprint(w, `
+Run "enspired help command" for more about a command.
+
+Exit codes: 0 all good, 1 invalid plan, 2 bad command line, 3 I/O error, 4 parse error.
+
+Flags:
+`)
+	printFlags()
+}
+
+// help is the help command: the usage of the tool, or of the given command, on the standard output.
+func (c *cli) help(args []string, printFlags func()) int {
+	if len(args) == 0 {
+		c.usage(c.stdout, printFlags)
+		return exitOK
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			helped := *c
+			helped.stderr = c.stdout
+			return cmd.run(&helped, []string{"-h"})
+		}
+	}
+	c.report("Unknown command %s", args[0])
+	return exitUsage
+}
+
+// parse is the parse command, and what runs when no command is given: it prints the chairs in each room of the plan.
+func (c *cli) parse(args []string) int {
+	flags := c.flagSet("parse", "parse [-json] [-debug-overlay] file",
+		"Counts the chairs of each type in the whole plan, then in each room.\n"+
+			"The totals of each floor, apartment and room category are only in the JSON, the text keeps to the old system's result format.")
+	asJSON := flags.Bool("json", false, "print the result as JSON, along with the area and the chairs' positions of each room")
+	debugOverlay := flags.Bool("debug-overlay", false,
+		"instead of the chair counts, reprint the plan with the cells of each room marked with the room's letter")
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	if flags.NArg() != 1 {
+		return usageError(flags)
+	}
+	name := flags.Arg(0)
+
+	parser := c.newParser()
+	plan, status := c.readPlanWith(parser, name)
+	if status != exitOK {
+		return status
+	}
+
+	if *debugOverlay {
+		// the overlay is for finding out what's wrong with a plan, so problems don't make it fail
+		if err := plan.Overlay(c.stdout, isTerminal(c.stdout)); err != nil {
+			c.report("Could not write the overlay: %v", err)
+			return exitIO
+		}
+		return exitOK
+	}
+
+	var err error
+	if *asJSON {
+		encoder := json.NewEncoder(c.stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(plan.Result())
+	} else {
+		_, err = fmt.Fprintf(c.stdout, "%s\n", parser)
+	}
+	if err != nil {
+		c.report("Could not write the chair counts: %v", err)
+		return exitIO
+	}
+	return c.reportProblems(name, plan)
 }
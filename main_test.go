@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files with what the commands print now")
+
+// TestCLI runs the commands in-process and compares what they print and exit with to testdata/golden.
+// After an intended change in the output, go test -run TestCLI -update rewrites the golden files.
+func TestCLI(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		// the file the standard input reads from, if any
+		stdin string
+	}{
+		{name: "no-command", args: []string{"testdata/plan.txt"}},
+		{name: "parse", args: []string{"parse", "testdata/plan.txt"}},
+		{name: "parse-json", args: []string{"parse", "-json", "testdata/plan.txt"}},
+		{name: "parse-stdin", args: []string{"parse", "-"}, stdin: "testdata/plan.txt"},
+		{name: "parse-building", args: []string{"parse", "testdata/building.txt"}},
+		{name: "parse-apartments", args: []string{"parse", "testdata/apartments.txt"}},
+		{name: "stats-apartments", args: []string{"stats", "testdata/apartments.txt"}},
+		{name: "parse-debug-overlay", args: []string{"--debug-overlay", "testdata/plan.txt"}},
+		{name: "parse-broken", args: []string{"parse", "testdata/broken.txt"}},
+		{name: "parse-broken-verbose", args: []string{"--verbose", "parse", "testdata/broken.txt"}},
+		{name: "parse-broken-quiet", args: []string{"--quiet", "parse", "testdata/broken.txt"}},
+		{name: "parse-open", args: []string{"parse", "testdata/open.txt"}},
+		{name: "parse-missing-file", args: []string{"parse", "testdata/missing.txt"}},
+		{name: "parse-too-many-files", args: []string{"parse", "testdata/plan.txt", "testdata/open.txt"}},
+		{name: "validate", args: []string{"validate", "testdata/plan.txt", "testdata/open.txt", "testdata/broken.txt"}},
+		{name: "validate-json", args: []string{"validate", "-json", "testdata/plan.txt", "testdata/open.txt"}},
+		{name: "validate-stdin", args: []string{"validate", "-"}, stdin: "testdata/open.txt"},
+		{name: "validate-rules", args: []string{"-categories", "testdata/categories.json", "validate", "-rules", "testdata/rules.json", "testdata/apartments.txt"}},
+		{name: "validate-rules-json", args: []string{"-categories", "testdata/categories.json", "validate", "-json", "-rules", "testdata/rules.json", "testdata/apartments.txt"}},
+		{name: "validate-rules-no-categories", args: []string{"validate", "-rules", "testdata/rules.json", "testdata/apartments.txt"}},
+		{name: "validate-blocked", args: []string{"validate", "testdata/blocked.txt"}},
+		{name: "render", args: []string{"render", "testdata/plan.txt"}},
+		{name: "fmt", args: []string{"fmt", "testdata/plan-v2.txt"}},
+		{name: "fmt-check", args: []string{"fmt", "-check", "testdata/plan.txt", "testdata/plan-v2.txt"}},
+		{name: "fmt-worst-status", args: []string{"fmt", "testdata/broken.txt", "testdata/missing.txt"}},
+		{name: "fmt-worst-status-missing-first", args: []string{"fmt", "testdata/missing.txt", "testdata/broken.txt"}},
+		{name: "diff", args: []string{"diff", "testdata/plan.txt", "testdata/plan-v2.txt"}},
+		{name: "diff-json", args: []string{"diff", "-json", "testdata/plan.txt", "-"}, stdin: "testdata/plan-v2.txt"},
+		{name: "diff-old-result", args: []string{"diff", "-old-result", "testdata/result.txt", "testdata/plan-v2.txt"}},
+		{name: "diff-old-result-building", args: []string{"diff", "-old-result", "testdata/building-result.txt", "testdata/building.txt"}},
+		{name: "stats", args: []string{"stats", "testdata/plan.txt"}},
+		{name: "stats-building", args: []string{"stats", "testdata/building.txt"}},
+		{name: "stats-categories", args: []string{"-categories", "testdata/categories.json", "stats", "testdata/building.txt"}},
+		{name: "parse-json-categories", args: []string{"-categories", "testdata/categories.json", "parse", "-json", "testdata/plan.txt"}},
+		{name: "categories-order", args: []string{"-categories", "testdata/categories.json", "order", "testdata/plan.txt"}},
+		{name: "bad-categories", args: []string{"-categories", "testdata/plan.txt", "parse", "testdata/plan.txt"}},
+		{name: "stats-json", args: []string{"stats", "-json", "testdata/plan.txt"}},
+		{name: "stats-max-density", args: []string{"stats", "-max-density", "20", "testdata/plan.txt"}},
+		{name: "export", args: []string{"export", "testdata/plan.txt"}},
+		{name: "export-layout", args: []string{"export", "-layout", "testdata/layout.json", "testdata/plan.txt"}},
+		{name: "order-apartments", args: []string{"order", "testdata/apartments.txt"}},
+		{name: "where", args: []string{"where", "testdata/plan.txt", "6", "15"}},
+		{name: "help", args: []string{"--help"}},
+		{name: "help-command", args: []string{"help", "validate"}},
+		{name: "command-help", args: []string{"diff", "-h"}},
+		{name: "bad-flag", args: []string{"stats", "-xml", "testdata/plan.txt"}},
+		{name: "output-lsp", args: []string{"-o", "testdata/never-written.txt", "lsp"}},
+		{name: "output-view", args: []string{"-o", "testdata/never-written.txt", "view", "testdata/plan.txt"}},
+		{name: "no-arguments", args: []string{}},
+		{name: "version", args: []string{"--version"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdin []byte
+			if tt.stdin != "" {
+				var err error
+				if stdin, err = os.ReadFile(tt.stdin); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var stdout, stderr bytes.Buffer
+			c := &cli{stdin: bytes.NewReader(stdin), stdout: &stdout, stderr: &stderr}
+			status := c.run(tt.args)
+
+			got := fmt.Sprintf("$ enspired %s\nexit: %d\n-- stdout --\n%s-- stderr --\n%s",
+				strings.Join(tt.args, " "), status, stdout.String(), stderr.String())
+			golden := filepath.Join("testdata", "golden", tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("output differs from %s:\n%s\nwant:\n%s", golden, got, want)
+			}
+		})
+	}
+}
+
+func TestCLI_Output(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "result.json")
+	run := func(args ...string) int {
+		var stdout, stderr bytes.Buffer
+		c := &cli{stdin: bytes.NewReader(nil), stdout: &stdout, stderr: &stderr}
+		status := c.run(append([]string{"-o", output}, args...))
+		if stdout.Len() != 0 {
+			t.Errorf("run(%q) printed %q, want it in the file", args, stdout.String())
+		}
+		return status
+	}
+
+	if status := run("parse", "-json", "testdata/plan.txt"); status != exitOK {
+		t.Fatalf("parse exited with %d, want %d", status, exitOK)
+	}
+	golden, err := os.ReadFile("testdata/golden/parse-json.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, want, _ := strings.Cut(string(golden), "-- stdout --\n")
+	want, _, _ = strings.Cut(want, "-- stderr --\n")
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("the file has:\n%s\nwant the standard output of testdata/golden/parse-json.golden:\n%s", got, want)
+	}
+
+	// a failing command leaves the file as it was, and no temporary file behind
+	if status := run("parse", "-json", "testdata/broken.txt"); status != exitParse {
+		t.Fatalf("parse exited with %d, want %d", status, exitParse)
+	}
+	after, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(after, got) {
+		t.Errorf("the file changed to %q after a failed parse", after)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("files left in the directory: %v, want only result.json", entries)
+	}
+}
+
+// TestCLI_ResultRoundTrip reads what parse prints back with diff -old-result: the totals may not have changed.
+// Rooms without a name of their own are told apart by their cells, which the result doesn't have,
+// so they may come out as removed and added again.
+func TestCLI_ResultRoundTrip(t *testing.T) {
+	for _, tt := range []struct {
+		plan string
+		// the global flags of the parse run
+		flags []string
+	}{
+		{plan: "testdata/plan.txt"},
+		{plan: "testdata/building.txt"},
+		{plan: "testdata/apartments.txt"},
+		{plan: "testdata/building.txt", flags: []string{"-categories", "testdata/categories.json"}},
+	} {
+		t.Run(strings.Join(append(tt.flags, tt.plan), " "), func(t *testing.T) {
+			result := filepath.Join(t.TempDir(), "result.txt")
+			var stdout, stderr bytes.Buffer
+			c := &cli{stdin: bytes.NewReader(nil), stdout: &stdout, stderr: &stderr}
+			if status := c.run(append(tt.flags, "-o", result, "parse", tt.plan)); status != exitOK {
+				t.Fatalf("parse exited with %d: %s", status, stderr.String())
+			}
+			c = &cli{stdin: bytes.NewReader(nil), stdout: &stdout, stderr: &stderr}
+			if status := c.run([]string{"diff", "-old-result", result, tt.plan}); status != exitOK {
+				t.Fatalf("diff exited with %d: %s", status, stderr.String())
+			}
+			if want := "total: W: +0, P: +0, S: +0, C: +0\n"; !strings.HasSuffix(stdout.String(), want) {
+				t.Errorf("diff printed %q, want it to end with %q", stdout.String(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// atomicFile is an output file that appears all at once: what's written goes to a temporary file
+// next to it, which replaces the file on commit. Until then, whoever reads the file sees the previous version,
+// or no file at all, never a partly written one.
+type atomicFile struct {
+	*os.File
+	name string
+}
+
+// createAtomic starts writing the named file. The temporary file is in the same directory,
+// as a rename is only atomic within a file system.
+func createAtomic(name string) (*atomicFile, error) {
+	dir, base := filepath.Split(name)
+	if dir == "" {
+		dir = "."
+	}
+	// the leading dot keeps the temporary file out of the way of globs like *.txt
+	file, err := os.CreateTemp(dir, "."+base+".tmp*")
+	if err != nil {
+		return nil, err
+	}
+	return &atomicFile{File: file, name: name}, nil
+}
+
+// commit puts what was written in place of the file. The file keeps its permissions if it existed,
+// or else gets the usual 0644.
+func (f *atomicFile) commit() error {
+	mode := fs.FileMode(0o644)
+	if info, err := os.Stat(f.name); err == nil {
+		mode = info.Mode().Perm()
+	}
+	err := errors.Join(f.Chmod(mode), f.Sync(), f.Close())
+	if err == nil {
+		err = os.Rename(f.File.Name(), f.name)
+	}
+	if err != nil {
+		_ = os.Remove(f.File.Name())
+	}
+	return err
+}
+
+// abort throws away what was written, leaving the file as it was.
+func (f *atomicFile) abort() {
+	_ = f.Close()
+	_ = os.Remove(f.File.Name())
+}
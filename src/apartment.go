@@ -0,0 +1,138 @@
+package src
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Apartment is a group of rooms that open into each other, named by the label in one of them, like [apt 3B].
+type Apartment struct {
+	Name string
+	// Rooms are the apartment's rooms, sorted by name like the plan's.
+	Rooms []*Room
+}
+
+var apartmentLabelPattern = regexp.MustCompile(`^\s*apt\s+(\S(?:.*\S)?)\s*$`)
+
+// apartmentLabel tells whether the text between the brackets of a label names an apartment, and which if so.
+func apartmentLabel(label string) (string, bool) {
+	match := apartmentLabelPattern.FindStringSubmatch(label)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// isStairwell tells whether the room is a stairwell (or staircase, or stairs): a room every apartment of the storey
+// opens into, that belongs to none of them.
+func isStairwell(room *Room) bool {
+	return strings.HasPrefix(strings.ToLower(room.localName()), "stair")
+}
+
+// Apartments groups the plan's rooms into apartments, sorted by name.
+//
+// Rooms are in the same apartment when a door (#) in the wall between them connects them,
+// or a chain of doors through other rooms does, stairwells left out.
+// A room with a label is in the labelled apartment, a room without one in the apartment of the label
+// it's connected to. Rooms connected to no label, or to several different ones, are in no apartment.
+// The same label in rooms that aren't connected still makes one apartment, like a maisonette over two floors.
+func (p *Plan) Apartments() []*Apartment {
+	apartments, _ := p.apartments()
+	return apartments
+}
+
+// apartments is Apartments, along with a problem for every label connected to a different one.
+func (p *Plan) apartments() ([]*Apartment, []*ParseError) {
+	parent := make([]int, len(p.Rooms))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	cells := p.cellRooms()
+	for l, line := range p.Lines {
+		for c := 0; c < len(line); c++ {
+			if !IsDoor(rune(line[c])) {
+				continue
+			}
+			door := Position{Line: l + 1, Column: c + 1}
+			for _, sides := range [][2]Position{
+				{{Line: door.Line, Column: door.Column - 1}, {Line: door.Line, Column: door.Column + 1}},
+				{{Line: door.Line - 1, Column: door.Column}, {Line: door.Line + 1, Column: door.Column}},
+			} {
+				i, found := cells[sides[0]]
+				j, alsoFound := cells[sides[1]]
+				if found && alsoFound && !isStairwell(p.Rooms[i]) && !isStairwell(p.Rooms[j]) {
+					parent[find(i)] = find(j)
+				}
+			}
+		}
+	}
+
+	// the first label found in each group of connected rooms, and the problems with the others
+	labels := map[int]*Room{}
+	conflicts := map[int]bool{}
+	var problems []*ParseError
+	for i, room := range p.Rooms {
+		if room.Apartment == "" {
+			continue
+		}
+		first, found := labels[find(i)]
+		if !found {
+			labels[find(i)] = room
+			continue
+		}
+		if first.Apartment != room.Apartment {
+			conflicts[find(i)] = true
+			problems = append(problems, &ParseError{
+				Position: room.Label.Position,
+				Message: fmt.Sprintf("apartment %s opens into apartment %s (%s)",
+					room.Apartment, first.Apartment, displayName(first.Name)),
+			})
+		}
+	}
+
+	byName := map[string]*Apartment{}
+	for i, room := range p.Rooms {
+		name := room.Apartment
+		if label, found := labels[find(i)]; name == "" && found && !conflicts[find(i)] && !isStairwell(room) {
+			name = label.Apartment
+		}
+		if name == "" {
+			continue
+		}
+		if byName[name] == nil {
+			byName[name] = &Apartment{Name: name}
+		}
+		byName[name].Rooms = append(byName[name].Rooms, room)
+	}
+
+	apartments := make([]*Apartment, 0, len(byName))
+	for _, apartment := range byName {
+		apartments = append(apartments, apartment)
+	}
+	sort.Slice(apartments, func(i, j int) bool { return apartments[i].Name < apartments[j].Name })
+	return apartments, problems
+}
+
+// Totals counts the chairs of all the apartment's rooms, by type.
+func (a *Apartment) Totals() map[rune]int {
+	return roomTotals(a.Rooms)
+}
+
+// String shows the apartment's totals, then its rooms:
+//
+// apartment 3B:
+// W: 3, P: 2, S: 0, C: 0
+// rooms: bathroom, kitchen, living room
+func (a *Apartment) String() string {
+	return groupString("apartment", a.Name, a.Rooms)
+}
@@ -0,0 +1,98 @@
+package src
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPlan_Apartments(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		// the apartments with their rooms, as "name: room, room"
+		want     []string
+		problems []string
+	}{
+		{
+			name: "doors and a stairwell",
+			input: `+-----------+---------+-----------+
+| (kitchen) |         |  (living) |
+|  W  W     |(stairs) |  P        |
+|  [apt 1]  #         #  [apt 2]  |
++-----#-----+    C    +-----#-----+
+| (bedroom) |         |  (bath)   |
+|  S        #         |           |
++-----------+---------+-----------+`,
+			want: []string{"1: bedroom, kitchen", "2: bath, living"},
+		},
+		{
+			name: "rooms without a door stay out",
+			input: `+-----------+-----------+
+| (kitchen) | (pantry)  |
+|  [apt 1]  |           |
++-----------+-----------+`,
+			want: []string{"1: kitchen"},
+		},
+		{
+			name: "apartments opening into each other",
+			input: `+-----------+-----------+-----------+
+| (kitchen) | (hall)    | (kitchen) |
+|  [apt 1]  #           #  [apt 2]  |
++-----------+-----------+-----------+`,
+			want:     []string{"1: kitchen", "2: kitchen"},
+			problems: []string{"line 3, column 28: apartment 2 opens into apartment 1 (kitchen)"},
+		},
+		{
+			name: "maisonette",
+			input: `== floor 1 ==
++-----------+
+| (living)  |
+|  [apt 7]  |
++-----------+
+== floor 2 ==
++-----------+-----------+
+| (bedroom) #  (bath)   |
+|  [apt 7]  |           |
++-----------+-----------+`,
+			want: []string{"7: 1/living, 2/bath, 2/bedroom"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := ParsePlan(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, apartment := range plan.Apartments() {
+				var names []string
+				for _, room := range apartment.Rooms {
+					names = append(names, room.Name)
+				}
+				got = append(got, apartment.Name+": "+strings.Join(names, ", "))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apartments() = %q, want %q", got, tt.want)
+			}
+			var problems []string
+			for _, problem := range plan.Problems() {
+				problems = append(problems, problem.Position.String()+": "+problem.Message)
+			}
+			if !reflect.DeepEqual(problems, tt.problems) {
+				t.Errorf("Problems() = %q, want %q", problems, tt.problems)
+			}
+		})
+	}
+}
+
+func TestApartmentLabel(t *testing.T) {
+	_, err := ParsePlan(strings.NewReader("+--------+\n| [flat] |\n+--------+"))
+	if err == nil || !strings.HasSuffix(err.Error(), "label [flat] is not an apartment label like [apt 3B]") {
+		t.Errorf("ParsePlan() error = %v, want one about the label", err)
+	}
+	_, err = ParsePlan(strings.NewReader("+--------+\n| [apt 1 |\n+--------+"))
+	if err == nil || !strings.HasSuffix(err.Error(), "apartment label did not close. It starts with 'apt 1 '") {
+		t.Errorf("ParsePlan() error = %v, want one about the label", err)
+	}
+}
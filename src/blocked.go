@@ -0,0 +1,77 @@
+package src
+
+import "fmt"
+
+// blockedChairs lists the chairs that are in the way when carrying furniture through the plan:
+// the ones in front of a door (#), the ones in a gap of a wall, where the wall goes on at both sides of them,
+// and the ones with no free floor cell, one without a chair, next to them.
+// Each chair is reported once, at its own position. Plans without lines have none.
+func (p *Plan) blockedChairs() []*ParseError {
+	var problems []*ParseError
+	if len(p.Lines) == 0 {
+		return nil
+	}
+	for _, room := range p.Rooms {
+		for _, chair := range room.Placements {
+			if message := p.blocked(chair); message != "" {
+				chairType, _ := ChairTypeOf(chair.Type)
+				problems = append(problems, &ParseError{
+					Position: chair.Position,
+					Message:  fmt.Sprintf("%s %s (%s)", chairType.Name, message, displayName(room.Name)),
+				})
+			}
+		}
+	}
+	return problems
+}
+
+// blocked tells what the chair blocks, if anything.
+func (p *Plan) blocked(chair Chair) string {
+	at := chair.Position
+	left, right := Position{Line: at.Line, Column: at.Column - 1}, Position{Line: at.Line, Column: at.Column + 1}
+	up, down := Position{Line: at.Line - 1, Column: at.Column}, Position{Line: at.Line + 1, Column: at.Column}
+	neighbours := []Position{left, right, up, down}
+
+	for _, pos := range neighbours {
+		if IsDoor(p.At(pos)) {
+			return "blocks the door at " + pos.String()
+		}
+	}
+	// a gap is where a wall would go on through the chair: a narrow room only has walls on both sides
+	if p.inGap(at, 0, 1, '-') || p.inGap(at, 1, 0, '|') {
+		return "sits in a gap of the wall"
+	}
+	for _, pos := range neighbours {
+		if _, kind := p.RoomAt(pos); kind == Floor && !IsChair(p.At(pos)) {
+			return ""
+		}
+	}
+	return "has no free floor next to it"
+}
+
+// inGap tells whether the cell at pos is in an opening of a wall, however wide: walking along the wall's line,
+// a step being the given lines and columns, past the chairs and blanks of the opening, both ends are walls running
+// that way. A junction where a wall across the line goes on at both sides is the side of a room, not the end of a wall:
+// a chair in a room as narrow as a wall's gap, like a stairwell between two apartments, is not in a gap.
+func (p *Plan) inGap(pos Position, lines, columns int, straight rune) bool {
+	wallEnd := func(direction int) bool {
+		at := pos
+		for {
+			at = Position{Line: at.Line + direction*lines, Column: at.Column + direction*columns}
+			if at.Line < 1 || at.Line > len(p.Lines) || at.Column < 1 || at.Column > len(p.Lines[at.Line-1]) {
+				return false
+			}
+			if c := p.At(at); c != ' ' && !IsChair(c) {
+				before := p.At(Position{Line: at.Line - columns, Column: at.Column - lines})
+				after := p.At(Position{Line: at.Line + columns, Column: at.Column + lines})
+				return isWallAlong(c, straight) && !(IsWall(before) && IsWall(after))
+			}
+		}
+	}
+	return wallEnd(-1) && wallEnd(1)
+}
+
+// isWallAlong tells whether c is a wall running in the direction of the straight wall glyph, or a junction of walls.
+func isWallAlong(c rune, straight rune) bool {
+	return c == straight || c == '+'
+}
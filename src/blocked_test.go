@@ -0,0 +1,115 @@
+package src
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPlan_BlockedChairs(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name: "free",
+			input: `+------+---+
+|(a) W |  C|
+|   SP |   |
++------+---+`,
+		},
+		{
+			name: "door",
+			input: `+------+---+
+|(a)  W#  C|
++------+---+`,
+			want: []string{"line 2, column 7: wooden chair blocks the door at line 2, column 8 (a)"},
+		},
+		{
+			name: "gap",
+			input: `+-----+-----+
+|(a)  |(b)  |
+|     W     |
+|     |  P  |
++-----+-----+`,
+			want: []string{"line 3, column 7: wooden chair sits in a gap of the wall (a)"},
+		},
+		{
+			name: "2-wide gap",
+			input: `+----------+
+|(a)       |
++--+WP+----+
+|          |
++----------+`,
+			want: []string{
+				"line 3, column 5: wooden chair sits in a gap of the wall (a)",
+				"line 3, column 6: plastic chair sits in a gap of the wall (a)",
+			},
+		},
+		{
+			name: "3-wide gap",
+			input: `+-----+-----+
+|(a)  |(b)  |
+|     W     |
+|      P    |
+|     S     |
+|     |     |
++-----+-----+`,
+			want: []string{
+				"line 3, column 7: wooden chair sits in a gap of the wall (a)",
+				"line 5, column 7: sofa chair sits in a gap of the wall (a)",
+			},
+		},
+		{
+			name: "room between the sides of walls",
+			input: `+-----+---+-----+
+|(a)  |   |(b)  |
++-----+ C +-----+
+|(c)  |   |(d)  |
++-----+---+-----+`,
+		},
+		{
+			name: "narrow room",
+			input: `+-+----+
+|W|(a) |
+| |  C |
++-+----+`,
+		},
+		{
+			name: "hemmed in",
+			input: `+------+
+|(a) CW|
+|    WP|
++------+`,
+			want: []string{"line 2, column 7: wooden chair has no free floor next to it (a)", "line 3, column 7: plastic chair has no free floor next to it (a)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := ParsePlan(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var problems []string
+			for _, problem := range plan.Problems() {
+				problems = append(problems, problem.Position.String()+": "+problem.Message)
+			}
+			if !reflect.DeepEqual(problems, tt.want) {
+				t.Errorf("Problems() = %q, want %q", problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlan_BlockedChairsWithoutLines(t *testing.T) {
+	plan, err := ParsePlan(strings.NewReader("+---+\n|W#P|\n+---+"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan.Lines = nil
+	if problems := plan.Problems(); len(problems) != 0 {
+		t.Errorf("Problems() = %v, want none without the lines", problems)
+	}
+}
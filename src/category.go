@@ -0,0 +1,138 @@
+package src
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// OtherCategory is the category of the rooms no rule matches.
+const OtherCategory = "other"
+
+// CategoryRule puts the rooms whose name matches it in a category.
+// A rule matches in one way only: the whole name, its beginning, or a regular expression.
+// Names are matched without their floor, and regardless of case.
+type CategoryRule struct {
+	Category string `json:"category"`
+	Exact    string `json:"exact,omitempty"`
+	Prefix   string `json:"prefix,omitempty"`
+	Regex    string `json:"regex,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// Categories maps room names to categories. The first rule a name matches gives its category.
+type Categories struct {
+	Rules []CategoryRule `json:"rules"`
+}
+
+// ReadCategories decodes Categories from JSON:
+//
+//	{"rules": [
+//	  {"category": "kitchen", "exact": "kitchen"},
+//	  {"category": "bedroom", "prefix": "sleeping"},
+//	  {"category": "bathroom", "regex": "bath|toilet|wc"}
+//	]}
+func ReadCategories(reader io.Reader) (*Categories, error) {
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	categories := &Categories{}
+	if err := decoder.Decode(categories); err != nil {
+		return nil, fmt.Errorf("can't decode the categories: %w", err)
+	}
+	for i := range categories.Rules {
+		rule := &categories.Rules[i]
+		if rule.Category == "" {
+			return nil, fmt.Errorf("categories: rule %d has no category", i+1)
+		}
+		ways := 0
+		for _, way := range []string{rule.Exact, rule.Prefix, rule.Regex} {
+			if way != "" {
+				ways++
+			}
+		}
+		if ways != 1 {
+			return nil, fmt.Errorf("categories: rule %d (%s) needs one of exact, prefix or regex", i+1, rule.Category)
+		}
+		if rule.Regex != "" {
+			pattern, err := regexp.Compile("(?i)" + rule.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("categories: rule %d (%s): %w", i+1, rule.Category, err)
+			}
+			rule.pattern = pattern
+		}
+	}
+	return categories, nil
+}
+
+// Of is the category of a room name, OtherCategory if no rule matches it.
+func (c *Categories) Of(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, rule := range c.Rules {
+		switch {
+		case rule.Exact != "" && name == strings.ToLower(rule.Exact),
+			rule.Prefix != "" && strings.HasPrefix(name, strings.ToLower(rule.Prefix)),
+			rule.pattern != nil && rule.pattern.MatchString(name):
+			return rule.Category
+		}
+	}
+	return OtherCategory
+}
+
+// Categorize sets the category of every closed room of the plan.
+func (p *Plan) Categorize(categories *Categories) {
+	for _, room := range p.Rooms {
+		room.Category = categories.Of(room.localName())
+	}
+}
+
+// Category is a kind of room, along with the plan's rooms of that kind.
+type Category struct {
+	Name string
+	// Rooms are the category's rooms, sorted by name like the plan's.
+	Rooms []*Room
+}
+
+// Categories groups the plan's rooms by category, sorted by name, OtherCategory last.
+// It has none if the plan wasn't categorized.
+func (p *Plan) Categories() []*Category {
+	byName := map[string]*Category{}
+	for _, room := range p.Rooms {
+		if room.Category == "" {
+			continue
+		}
+		if byName[room.Category] == nil {
+			byName[room.Category] = &Category{Name: room.Category}
+		}
+		byName[room.Category].Rooms = append(byName[room.Category].Rooms, room)
+	}
+
+	categories := make([]*Category, 0, len(byName))
+	for _, category := range byName {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if (categories[i].Name == OtherCategory) != (categories[j].Name == OtherCategory) {
+			return categories[j].Name == OtherCategory
+		}
+		return categories[i].Name < categories[j].Name
+	})
+	return categories
+}
+
+// Totals counts the chairs of all the category's rooms, by type.
+func (c *Category) Totals() map[rune]int {
+	return roomTotals(c.Rooms)
+}
+
+// String shows the category's totals, then its rooms:
+//
+// category bedroom:
+// W: 1, P: 0, S: 2, C: 0
+// rooms: 1/bedroom, 2/sleeping room
+func (c *Category) String() string {
+	return groupString("category", c.Name, c.Rooms)
+}
@@ -0,0 +1,72 @@
+package src
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCategories_Of(t *testing.T) {
+	categories, err := ReadCategories(strings.NewReader(`{"rules": [
+		{"category": "kitchen", "exact": "Kitchen"},
+		{"category": "bedroom", "prefix": "sleeping"},
+		{"category": "bathroom", "regex": "bath|toilet|^wc$"},
+		{"category": "living", "prefix": "living"}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"kitchen":        "kitchen",
+		"KITCHEN":        "kitchen",
+		"kitchenette":    OtherCategory,
+		"sleeping room":  "bedroom",
+		"bathroom":       "bathroom",
+		"guest toilet":   "bathroom",
+		"wc":             "bathroom",
+		"wc closet":      OtherCategory,
+		"living room":    "living",
+		"":               OtherCategory,
+		" living room  ": "living",
+	} {
+		if got := categories.Of(name); got != want {
+			t.Errorf("Of(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestReadCategories_Errors(t *testing.T) {
+	for config, want := range map[string]string{
+		`{"rules": [{"exact": "kitchen"}]}`:                                             "categories: rule 1 has no category",
+		`{"rules": [{"category": "kitchen"}]}`:                                          "categories: rule 1 (kitchen) needs one of exact, prefix or regex",
+		`{"rules": [{"category": "kitchen", "exact": "a", "prefix": "b"}]}`:             "categories: rule 1 (kitchen) needs one of exact, prefix or regex",
+		`{"rules": [{"category": "k", "exact": "k"}, {"category": "b", "regex": "("}]}`: "categories: rule 2 (b): error parsing regexp: missing closing ): `(?i)(`",
+	} {
+		if _, err := ReadCategories(strings.NewReader(config)); err == nil || err.Error() != want {
+			t.Errorf("ReadCategories(%s) error = %v, want %s", config, err, want)
+		}
+	}
+}
+
+func TestPlan_Categories(t *testing.T) {
+	plan, err := ParsePlan(strings.NewReader(building))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := plan.Categories(); len(got) != 0 {
+		t.Errorf("Categories() of a plan not categorized = %v, want none", got)
+	}
+
+	plan.Categorize(&Categories{Rules: []CategoryRule{{Category: "kitchen", Exact: "kitchen"}}})
+	var got []string
+	for _, category := range plan.Categories() {
+		got = append(got, category.String())
+	}
+	// the floor is no part of the name the rules see
+	want := []string{
+		"category kitchen:\nW: 2, P: 0, S: 0, C: 0\nrooms: 1/kitchen",
+		"category other:\nW: 0, P: 0, S: 0, C: 1\nrooms: (no name)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Categories() =\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,47 @@
+package src
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChairType is a kind of chair, along with the letter plans draw it with.
+type ChairType struct {
+	Code rune
+	Name string
+}
+
+// ChairTypes is the catalog of the chairs plans can hold, in the order the old system lists them.
+var ChairTypes = []ChairType{
+	{Code: 'W', Name: "wooden chair"},
+	{Code: 'P', Name: "plastic chair"},
+	{Code: 'S', Name: "sofa chair"},
+	{Code: 'C', Name: "china chair"},
+}
+
+// ChairTypeOf looks up a chair type in the catalog by its letter.
+func ChairTypeOf(code rune) (ChairType, bool) {
+	for _, chairType := range ChairTypes {
+		if chairType.Code == code {
+			return chairType, true
+		}
+	}
+	return ChairType{}, false
+}
+
+// IsChair tells whether c is the letter of a chair type from the catalog.
+func IsChair(c rune) bool {
+	_, found := ChairTypeOf(c)
+	return found
+}
+
+// ChairCounts lists the counts of every chair type in the catalog, in catalog order, zeros included:
+//
+// W: 3, P: 0, S: 0, C: 0
+func ChairCounts(chairs map[rune]int) string {
+	pairs := make([]string, 0, len(ChairTypes))
+	for _, chairType := range ChairTypes {
+		pairs = append(pairs, fmt.Sprintf("%c: %d", chairType.Code, chairs[chairType.Code]))
+	}
+	return strings.Join(pairs, ", ")
+}
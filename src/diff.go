@@ -0,0 +1,197 @@
+package src
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// the ways a room can change between two versions of a plan
+const (
+	RoomAdded   = "added"
+	RoomRemoved = "removed"
+	RoomRenamed = "renamed"
+	RoomChanged = "changed"
+)
+
+// RoomChange is what happened to a room between two versions of a plan.
+type RoomChange struct {
+	Status string `json:"status"`
+	// Old is the room's name in the old version, New in the new one.
+	// Added rooms have no old name, removed rooms have no new one.
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+	// Chairs holds how many chairs of each type were added (positive) or taken out (negative).
+	// Types that didn't change are left out.
+	Chairs map[string]int `json:"chairs,omitempty"`
+}
+
+// PlanDiff is what changed between two versions of a plan.
+type PlanDiff struct {
+	// Rooms are the rooms that changed, ordered by name. Rooms that stayed the same are left out.
+	Rooms []RoomChange `json:"rooms"`
+	// Totals holds the change in the whole plan's count of every chair type in the catalog.
+	Totals map[string]int `json:"totals"`
+}
+
+// DiffPlans finds out what changed between two versions of a plan.
+//
+// Rooms are matched by name first. The ones left (renamed, unnamed or with a name that's not unique)
+// are matched with the room they share the most cells with, if any.
+func DiffPlans(before, after *Plan) *PlanDiff {
+	diff := &PlanDiff{Rooms: []RoomChange{}, Totals: map[string]int{}}
+	matchedOld := make([]bool, len(before.Rooms))
+	matchedNew := make([]bool, len(after.Rooms))
+
+	pair := func(i, j int) {
+		matchedOld[i], matchedNew[j] = true, true
+		oldRoom, newRoom := before.Rooms[i], after.Rooms[j]
+		change := RoomChange{Status: RoomChanged, Old: oldRoom.Name, New: newRoom.Name,
+			Chairs: chairDeltas(oldRoom.Chairs, newRoom.Chairs)}
+		if oldRoom.Name != newRoom.Name {
+			change.Status = RoomRenamed
+		} else if len(change.Chairs) == 0 {
+			return
+		}
+		diff.Rooms = append(diff.Rooms, change)
+	}
+
+	oldNames, newNames := roomNameCounts(before.Rooms), roomNameCounts(after.Rooms)
+	for i, oldRoom := range before.Rooms {
+		if oldRoom.Name == "" || oldNames[oldRoom.Name] > 1 || newNames[oldRoom.Name] != 1 {
+			continue
+		}
+		for j, newRoom := range after.Rooms {
+			if newRoom.Name == oldRoom.Name {
+				pair(i, j)
+			}
+		}
+	}
+
+	type candidate struct{ old, new, shared int }
+	var candidates []candidate
+	newCells := after.cellRooms()
+	for i, oldRoom := range before.Rooms {
+		if matchedOld[i] {
+			continue
+		}
+		shared := map[int]int{}
+		for _, cell := range oldRoom.Cells {
+			if j, found := newCells[cell]; found && !matchedNew[j] {
+				shared[j]++
+			}
+		}
+		for j, count := range shared {
+			candidates = append(candidates, candidate{i, j, count})
+		}
+	}
+	sort.Slice(candidates, func(a, b int) bool {
+		if candidates[a].shared != candidates[b].shared {
+			return candidates[a].shared > candidates[b].shared
+		}
+		if candidates[a].old != candidates[b].old {
+			return candidates[a].old < candidates[b].old
+		}
+		return candidates[a].new < candidates[b].new
+	})
+	for _, c := range candidates {
+		if !matchedOld[c.old] && !matchedNew[c.new] {
+			pair(c.old, c.new)
+		}
+	}
+
+	for i, room := range before.Rooms {
+		if !matchedOld[i] {
+			diff.Rooms = append(diff.Rooms, RoomChange{Status: RoomRemoved, Old: room.Name,
+				Chairs: chairDeltas(room.Chairs, nil)})
+		}
+	}
+	for j, room := range after.Rooms {
+		if !matchedNew[j] {
+			diff.Rooms = append(diff.Rooms, RoomChange{Status: RoomAdded, New: room.Name,
+				Chairs: chairDeltas(nil, room.Chairs)})
+		}
+	}
+	sort.SliceStable(diff.Rooms, func(a, b int) bool { return diff.Rooms[a].name() < diff.Rooms[b].name() })
+
+	oldTotals, newTotals := before.Totals(), after.Totals()
+	for _, chairType := range ChairTypes {
+		diff.Totals[string(chairType.Code)] = newTotals[chairType.Code] - oldTotals[chairType.Code]
+	}
+	return diff
+}
+
+// String lists the changes one room per line, then the change in totals:
+//
+// added pantry: W: +2
+// renamed office -> study
+// changed kitchen: W: +1, P: -1
+// total: W: +3, P: -1, S: +0, C: +0
+func (d *PlanDiff) String() string {
+	var lines []string
+	for _, change := range d.Rooms {
+		line := change.Status + " "
+		switch change.Status {
+		case RoomAdded:
+			line += displayName(change.New)
+		case RoomRenamed:
+			line += displayName(change.Old) + " -> " + displayName(change.New)
+		default:
+			line += displayName(change.Old)
+		}
+		if deltas := formatDeltas(change.Chairs, false); deltas != "" {
+			line += ": " + deltas
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "total: "+formatDeltas(d.Totals, true))
+	return strings.Join(lines, "\n")
+}
+
+func (c RoomChange) name() string {
+	if c.Status == RoomAdded {
+		return c.New
+	}
+	return c.Old
+}
+
+func displayName(name string) string {
+	if name == "" {
+		return "(no name)"
+	}
+	return name
+}
+
+// formatDeltas lists chair count changes in catalog order, the unchanged ones too if withZeros is set.
+func formatDeltas(deltas map[string]int, withZeros bool) string {
+	var pairs []string
+	for _, chairType := range ChairTypes {
+		delta := deltas[string(chairType.Code)]
+		if delta == 0 && !withZeros {
+			continue
+		}
+		pairs = append(pairs, fmt.Sprintf("%c: %+d", chairType.Code, delta))
+	}
+	return strings.Join(pairs, ", ")
+}
+
+func chairDeltas(before, after map[rune]int) map[string]int {
+	deltas := map[string]int{}
+	for _, chairType := range ChairTypes {
+		if delta := after[chairType.Code] - before[chairType.Code]; delta != 0 {
+			deltas[string(chairType.Code)] = delta
+		}
+	}
+	if len(deltas) == 0 {
+		return nil
+	}
+	return deltas
+}
+
+func roomNameCounts(rooms []*Room) map[string]int {
+	counts := map[string]int{}
+	for _, room := range rooms {
+		counts[room.Name]++
+	}
+	return counts
+}
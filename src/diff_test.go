@@ -0,0 +1,99 @@
+package src
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDiffPlans(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		want     string
+		wantJSON string
+	}{
+		{
+			name:     "nothing changed",
+			old:      "+-----+\n|(a) W|\n+-----+\n",
+			new:      "+-----+\n|(a) W|\n+-----+\n",
+			want:     "total: W: +0, P: +0, S: +0, C: +0",
+			wantJSON: `{"rooms":[],"totals":{"C":0,"P":0,"S":0,"W":0}}`,
+		},
+		{
+			name: "added and changed",
+			old: `+-----+-----+
+|(a) W|(b) P|
++-----+-----+`,
+			new: `+-----+-----+
+|(a) P|(c) S|
++-----+-----+
+|(b)  C     |
++-----------+`,
+			want: `changed a: W: -1, P: +1
+changed b: P: -1, C: +1
+added c: S: +1
+total: W: -1, P: +0, S: +1, C: +1`,
+			wantJSON: `{"rooms":[` +
+				`{"status":"changed","old":"a","new":"a","chairs":{"P":1,"W":-1}},` +
+				`{"status":"changed","old":"b","new":"b","chairs":{"C":1,"P":-1}},` +
+				`{"status":"added","new":"c","chairs":{"S":1}}],` +
+				`"totals":{"C":1,"P":0,"S":1,"W":-1}}`,
+		},
+		{
+			name: "renamed rooms are matched by the cells they share",
+			old: `+-----+-----+
+|(a) W|(b) P|
++-----+-----+`,
+			new: `+-----+-----+
+|(x) W|     |
++-----+-----+`,
+			want: `renamed a -> x
+renamed b -> (no name): P: -1
+total: W: +0, P: -1, S: +0, C: +0`,
+			wantJSON: `{"rooms":[` +
+				`{"status":"renamed","old":"a","new":"x"},` +
+				`{"status":"renamed","old":"b","chairs":{"P":-1}}],` +
+				`"totals":{"C":0,"P":-1,"S":0,"W":0}}`,
+		},
+		{
+			name: "removed",
+			old: `+-----+-----+
+|(a) W|(b) P|
++-----+-----+`,
+			new: `+-----------+
+|(a) W      |
++-----------+`,
+			want: `removed b: P: -1
+total: W: +0, P: -1, S: +0, C: +0`,
+			wantJSON: `{"rooms":[{"status":"removed","old":"b","chairs":{"P":-1}}],` +
+				`"totals":{"C":0,"P":-1,"S":0,"W":0}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, err := ParsePlan(strings.NewReader(tt.old))
+			if err != nil {
+				t.Fatal(err)
+			}
+			after, err := ParsePlan(strings.NewReader(tt.new))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			diff := DiffPlans(before, after)
+			if got := diff.String(); got != tt.want {
+				t.Errorf("DiffPlans() =\n%s\nwant:\n%s", got, tt.want)
+			}
+			gotJSON, err := json.Marshal(diff)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(gotJSON) != tt.wantJSON {
+				t.Errorf("DiffPlans() JSON =\n%s\nwant:\n%s", gotJSON, tt.wantJSON)
+			}
+		})
+	}
+}
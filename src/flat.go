@@ -2,8 +2,10 @@ package src
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"sort"
 	"strings"
 )
@@ -12,6 +14,17 @@ import (
 type roomData struct {
 	Name   string
 	Chairs map[rune]int
+
+	// where the room was drawn.
+	// Only rooms built by the parser out of line segments have these, the totals don't.
+	cells  []Position
+	chairs []Chair
+	title  Span
+	// the floor the room is on, if the plan has floors
+	floor string
+	// the apartment the room's label names, and where the label is
+	apartment string
+	label     Span
 }
 
 // The string representation of a room's data.
@@ -20,11 +33,12 @@ type roomData struct {
 // living room:
 // W: 3, P: 0, S: 0, C: 0
 func (d *roomData) String() string {
+	name := floorRoomName(d.floor, d.Name)
 	if len(d.Chairs) == 0 {
-		if d.Name == "" {
+		if name == "" {
 			return "(no data)"
 		}
-		return d.Name
+		return name
 	}
 	var pairs []string
 	keys := make([]rune, 0, len(d.Chairs))
@@ -35,24 +49,53 @@ func (d *roomData) String() string {
 	for _, key := range keys {
 		pairs = append(pairs, fmt.Sprintf("%c: %d", key, d.Chairs[key]))
 	}
-	return fmt.Sprintf("%s:\n%s", d.Name, strings.Join(pairs, ", "))
+	return fmt.Sprintf("%s:\n%s", name, strings.Join(pairs, ", "))
 }
 
-func (d *roomData) appendDataFromSegments(segments LineSegments) error {
+func (d *roomData) appendDataFromSegments(line int, segments LineSegments) error {
 	for _, segment := range segments {
 		data, err := segmentData(segment.content)
 		if err != nil {
+			var parseErr *ParseError
+			if errors.As(err, &parseErr) {
+				parseErr.Position = Position{Line: line, Column: segment.start + parseErr.Column + 1}
+			}
 			return fmt.Errorf("[segment: '%s'] error parsing segment: %w", segment.content, err)
 		}
 		d.append(data)
+		d.appendGeometry(line, segment, data)
 	}
 	return nil
 }
 
+// appendGeometry records the cells of a segment found on the given line,
+// along with the chairs and title segmentData found in it.
+func (d *roomData) appendGeometry(line int, segment *segment, data *roomData) {
+	at := func(pos Position) Position {
+		return Position{Line: line, Column: segment.start + pos.Column + 1}
+	}
+	// columns are byte offsets, like everywhere else: a multibyte rune takes a cell per byte
+	for i := 0; i < len(segment.content); i++ {
+		d.cells = append(d.cells, at(Position{Column: i}))
+	}
+	for _, chair := range data.chairs {
+		d.chairs = append(d.chairs, Chair{Type: chair.Type, Position: at(chair.Position)})
+	}
+	if data.title.Length > 0 {
+		d.title = Span{Position: at(data.title.Position), Length: data.title.Length}
+	}
+	if data.label.Length > 0 {
+		d.label = Span{Position: at(data.label.Position), Length: data.label.Length}
+	}
+}
+
 func (d *roomData) append(d2 *roomData) {
 	if d2.Name != "" {
 		d.Name = d2.Name
 	}
+	if d2.apartment != "" {
+		d.apartment = d2.apartment
+	}
 	if d.Chairs == nil {
 		d.Chairs = map[rune]int{}
 	}
@@ -74,6 +117,8 @@ type openRoom struct {
 	// The parser will take in line after line, and will record the evolution of the openRoom's set of segments,
 	// so it will know at any time the exact state of an open room's segments
 	segments LineSegments
+	// tells the room apart in traces, as long as it has no name
+	id int
 }
 
 type FlatParser struct {
@@ -84,6 +129,24 @@ type FlatParser struct {
 	// All rooms will be closed by the end.
 	closedRooms []*roomData
 	Line        int
+	// Logger, when set, gets a debug record for every decision the parser makes:
+	// the segments of each line, which open room each of them went to, which rooms were opened and closed.
+	Logger *slog.Logger
+	// OnRoomOpened, when set, is called as soon as a line shows a room that wasn't there before,
+	// with what the parser knows of the room from that line alone.
+	OnRoomOpened func(room *Room)
+	// OnRoomClosed, when set, is called as soon as a room is closed, with everything there is to know about it.
+	// Consumers can act on finished rooms while the rest of the plan is still streaming in.
+	OnRoomClosed func(room *Room)
+	// OnError, when set, is called with every error Ingest returns, right before returning it.
+	OnError func(err error)
+	// how many rooms were opened so far, for numbering them in traces
+	opened int
+	// the floor the lines being ingested are on, empty until the first floor header
+	floor  string
+	floors []Storey
+	// the rooms that were still open when their floor ended
+	unclosed []*roomData
 }
 
 func NewRoomParser() *FlatParser {
@@ -102,23 +165,39 @@ func newRoomData() *roomData {
 // - changes in the room walls positions (so we always know which segments belong to which open room)
 // - room data (title, chairs, whatever interesting in the respective room segment)
 func (p *FlatParser) Ingest(line string) error {
+	err := p.ingest(line)
+	if err != nil && p.OnError != nil {
+		p.OnError(err)
+	}
+	return err
+}
+
+func (p *FlatParser) ingest(line string) error {
 	p.Line++
+	if name, found := floorHeader(line); found {
+		return p.startFloor(name)
+	}
 
 	lineSegments := Split(line)
+	p.trace("line", "segments", lineSegments)
 
-	for _, room := range p.OpenRooms {
+	// closing a room removes it from p.OpenRooms, so range over a copy
+	for _, room := range append([]*openRoom{}, p.OpenRooms...) {
 		overlaps, rest := MultipleOverlaps(room.segments, lineSegments)
 		if len(overlaps) == 0 {
+			p.trace("room closed", "room", room.id, "name", room.RoomData.Name)
 			if err := p.closeRoom(room); err != nil {
 				return fmt.Errorf("can't close room: %w", err)
 			}
 			continue
 		}
 
-		if err := room.RoomData.appendDataFromSegments(overlaps); err != nil {
+		if err := room.RoomData.appendDataFromSegments(p.Line, overlaps); err != nil {
 			return fmt.Errorf("error ingesting segments: %w", err)
 		}
 
+		p.trace("segments matched", "room", room.id, "name", room.RoomData.Name, "segments", overlaps)
+
 		// keep the room's latest segments,
 		// so we can compute overlaps in further loop iterations
 		room.segments = overlaps
@@ -130,14 +209,20 @@ func (p *FlatParser) Ingest(line string) error {
 
 	// open rooms for each remaining (unassociated with previously opened rooms) lineSegment
 	for _, segment := range lineSegments {
-		data := &roomData{}
-		if err := data.appendDataFromSegments(LineSegments{segment}); err != nil {
+		data := &roomData{floor: p.floor}
+		if err := data.appendDataFromSegments(p.Line, LineSegments{segment}); err != nil {
 			return fmt.Errorf("[line %d] can't ingest segment: %w", p.Line, err)
 		}
+		p.opened++
 		p.OpenRooms = append(p.OpenRooms, &openRoom{
 			RoomData: data,
 			segments: LineSegments{segment},
+			id:       p.opened,
 		})
+		p.trace("room opened", "room", p.opened, "segments", LineSegments{segment})
+		if p.OnRoomOpened != nil {
+			p.OnRoomOpened(data.room())
+		}
 	}
 
 	return nil
@@ -160,6 +245,14 @@ func (p *FlatParser) IngestAllFromReader(reader *bufio.Reader) error {
 	return p.IngestAllFromReader(reader)
 }
 
+// trace logs a parser decision about the current line, if there's a Logger to log it to.
+func (p *FlatParser) trace(msg string, args ...any) {
+	if p.Logger == nil {
+		return
+	}
+	p.Logger.Debug(msg, append([]any{"line", p.Line}, args...)...)
+}
+
 func (p *FlatParser) closeRoom(room *openRoom) error {
 	for i, r := range p.OpenRooms {
 		if r != room {
@@ -167,6 +260,9 @@ func (p *FlatParser) closeRoom(room *openRoom) error {
 		}
 		p.closedRooms = append(p.closedRooms, p.OpenRooms[i].RoomData)
 		p.OpenRooms = append(p.OpenRooms[:i], p.OpenRooms[i+1:]...)
+		if p.OnRoomClosed != nil {
+			p.OnRoomClosed(room.RoomData.room())
+		}
 		break
 	}
 
@@ -182,12 +278,38 @@ func (p *FlatParser) totals(totalsEntryName string) *roomData {
 	return totals
 }
 
-func (p *FlatParser) String() string {
+// sortRooms orders the closed rooms by name, floor by floor.
+// A floor's rooms are all closed before the next floor starts, so the floors are in order already.
+func (p *FlatParser) sortRooms() {
 	// if this sorting is done at room close (the closeRoom method) instead of here,
 	// then it increases overall cpu usage with 90%
-	sort.Slice(p.closedRooms, func(i, j int) bool {
-		return p.closedRooms[i].Name < p.closedRooms[j].Name
-	})
+	for start := 0; start < len(p.closedRooms); {
+		end := start + 1
+		for end < len(p.closedRooms) && p.closedRooms[end].floor == p.closedRooms[start].floor {
+			end++
+		}
+		floor := p.closedRooms[start:end]
+		sort.SliceStable(floor, func(i, j int) bool {
+			return floor[i].Name < floor[j].Name
+		})
+		start = end
+	}
+}
+
+// Rooms returns the closed rooms, sorted by name.
+func (p *FlatParser) Rooms() []*Room {
+	p.sortRooms()
+	rooms := make([]*Room, 0, len(p.closedRooms))
+	for _, data := range p.closedRooms {
+		rooms = append(rooms, data.room())
+	}
+	return rooms
+}
+
+// String lists the chairs in the legacy format: the totals, then the rooms.
+// The old system knew nothing of floors: the totals of each floor are only in the plan's Result and Stats.
+func (p *FlatParser) String() string {
+	p.sortRooms()
 	roomStrings := []string{p.totals("total").String()}
 	for _, room := range p.closedRooms {
 		roomStrings = append(roomStrings, room.String())
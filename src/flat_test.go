@@ -1,6 +1,10 @@
 package src
 
 import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -114,6 +118,25 @@ func TestRoomParser_Ingest(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "closing a room doesn't skip its neighbour",
+			input: `
++---+---+---+
+|(a)|(b)|(c)|
++---+ W |   |
+    |   |   |
+    +---+---+
+`,
+			want: &FlatParser{
+				Line: 7,
+				closedRooms: []*roomData{
+					{Name: "a"},
+					{Name: "b", Chairs: map[rune]int{'W': 1}},
+					{Name: "c"},
+				},
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -158,6 +181,70 @@ func TestRoomParser_Ingest(t *testing.T) {
 		})
 	}
 }
+
+func TestRoomParser_Trace(t *testing.T) {
+	var traces bytes.Buffer
+	parser := NewRoomParser()
+	parser.Logger = slog.New(slog.NewTextHandler(&traces, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == slog.LevelKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	for _, line := range strings.Split("+---+---+\n|(a)| W |\n+---+   |\n    +---+", "\n") {
+		if err := parser.Ingest(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := `msg=line line=1 segments=""
+msg=line line=2 segments="[1: '(a)'] [5: ' W ']"
+msg="room opened" line=2 room=1 segments="[1: '(a)']"
+msg="room opened" line=2 room=2 segments="[5: ' W ']"
+msg=line line=3 segments="[5: '   ']"
+msg="room closed" line=3 room=1 name=a
+msg="segments matched" line=3 room=2 name="" segments="[5: '   ']"
+msg=line line=4 segments=""
+msg="room closed" line=4 room=2 name=""
+`
+	if got := traces.String(); got != want {
+		t.Errorf("traces:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRoomParser_Events(t *testing.T) {
+	var events []string
+	parser := NewRoomParser()
+	parser.OnRoomOpened = func(room *Room) {
+		events = append(events, fmt.Sprintf("line %d: opened %q", parser.Line, room.Name))
+	}
+	parser.OnRoomClosed = func(room *Room) {
+		events = append(events, fmt.Sprintf("line %d: closed %q, %s", parser.Line, room.Name, ChairCounts(room.Chairs)))
+	}
+	parser.OnError = func(err error) {
+		events = append(events, fmt.Sprintf("line %d: error %v", parser.Line, err))
+	}
+
+	for _, line := range strings.Split("+---+---+\n|(a)| W |\n+---+ P |\n    +---+\n|X|", "\n") {
+		_ = parser.Ingest(line)
+	}
+
+	want := []string{
+		`line 2: opened "a"`,
+		`line 2: opened ""`,
+		`line 3: closed "a", W: 0, P: 0, S: 0, C: 0`,
+		`line 4: closed "", W: 1, P: 1, S: 0, C: 0`,
+		`line 5: error [line 5] can't ingest segment: [segment: 'X'] error parsing segment: strange character encountered: X`,
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events:\n%s\nwant:\n%s", strings.Join(events, "\n"), strings.Join(want, "\n"))
+	}
+}
+
 func TestRoomString(t *testing.T) {
 	tt := []struct {
 		name string
@@ -0,0 +1,78 @@
+package src
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Storey is a floor of a building plan (Floor being the kind of cell): the lines after a header like
+// "== floor 2 ==", up to the next header. Each floor gets a room sweep of its own, and its rooms are named after it:
+// kitchen on floor 2 is "2/kitchen". Plans without headers have no floors, and their rooms keep their names as they are.
+type Storey struct {
+	Name string `json:"name"`
+	// Line is where the floor's header is.
+	Line int `json:"line"`
+}
+
+var floorHeaderPattern = regexp.MustCompile(`^\s*==\s*floor\s+(\S(?:.*\S)?)\s*==\s*$`)
+
+// floorHeader tells whether the line is a floor header, and the floor's name if so.
+func floorHeader(line string) (string, bool) {
+	match := floorHeaderPattern.FindStringSubmatch(line)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// floorRoomName is the name of a room on a floor, the floor's name first.
+// Rooms on no floor, and rooms without a name, keep their names.
+func floorRoomName(floor, name string) string {
+	if floor == "" || name == "" {
+		return name
+	}
+	return floor + "/" + name
+}
+
+// localName is the room's name as written in its title, without the floor.
+func (r *Room) localName() string {
+	if r.Floor == "" {
+		return r.Name
+	}
+	return strings.TrimPrefix(r.Name, r.Floor+"/")
+}
+
+// startFloor ends the current floor at a floor header: the rooms still open never close, and the next lines
+// are swept for rooms afresh.
+func (p *FlatParser) startFloor(name string) error {
+	for _, floor := range p.floors {
+		if floor.Name == name {
+			return &ParseError{Position: Position{Line: p.Line, Column: 1}, Message: "floor " + name + " is already in the plan"}
+		}
+	}
+	for _, room := range p.OpenRooms {
+		p.trace("room left open", "room", room.id, "name", room.RoomData.Name)
+		p.unclosed = append(p.unclosed, room.RoomData)
+	}
+	p.OpenRooms = []*openRoom{}
+	p.floor = name
+	p.floors = append(p.floors, Storey{Name: name, Line: p.Line})
+	p.trace("floor", "name", name)
+	return nil
+}
+
+// FloorTotals counts the chairs of the rooms on the named floor, by type.
+func (p *Plan) FloorTotals(floor string) map[rune]int {
+	return roomTotals(p.floorRooms(floor))
+}
+
+// floorRooms are the rooms on the named floor, sorted by name like the plan's.
+func (p *Plan) floorRooms(floor string) []*Room {
+	var rooms []*Room
+	for _, room := range p.Rooms {
+		if room.Floor == floor {
+			rooms = append(rooms, room)
+		}
+	}
+	return rooms
+}
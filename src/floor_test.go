@@ -0,0 +1,120 @@
+package src
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFloorHeader(t *testing.T) {
+	for _, tt := range []struct {
+		line  string
+		name  string
+		found bool
+	}{
+		{"== floor 2 ==", "2", true},
+		{"  ==floor   ground level==  ", "ground level", true},
+		{"== floor B-1 ==", "B-1", true},
+		{"== floor ==", "", false},
+		{"= floor 2 =", "", false},
+		{"| (floor 2) |", "", false},
+	} {
+		name, found := floorHeader(tt.line)
+		if name != tt.name || found != tt.found {
+			t.Errorf("floorHeader(%q) = %q, %v, want %q, %v", tt.line, name, found, tt.name, tt.found)
+		}
+	}
+}
+
+const building = `== floor 1 ==
++-----------+
+| (kitchen) |
+|  W     W  |
++-----------+
+== floor 2 ==
++-----------+------+
+| (kitchen) |(hall)|
+|  P        |      |
+== floor 3 ==
++-----+
+|  C  |
++-----+`
+
+func TestFlatParser_Floors(t *testing.T) {
+	parser := NewRoomParser()
+	plan, err := parser.ReadPlan(strings.NewReader(building))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantFloors := []Storey{{Name: "1", Line: 1}, {Name: "2", Line: 6}, {Name: "3", Line: 10}}
+	if !reflect.DeepEqual(plan.Floors, wantFloors) {
+		t.Errorf("Floors = %v, want %v", plan.Floors, wantFloors)
+	}
+	var names []string
+	for _, room := range plan.Rooms {
+		names = append(names, room.Name)
+	}
+	// the rooms of floor 2 never close: the floor ends before their bottom wall
+	if want := []string{"1/kitchen", ""}; !reflect.DeepEqual(names, want) {
+		t.Errorf("rooms = %v, want %v", names, want)
+	}
+	var unclosed []string
+	for _, room := range plan.Unclosed {
+		unclosed = append(unclosed, room.Name)
+	}
+	if want := []string{"2/kitchen", "2/hall"}; !reflect.DeepEqual(unclosed, want) {
+		t.Errorf("unclosed rooms = %v, want %v", unclosed, want)
+	}
+	if got := plan.Rooms[0].Placements[0].Position; got != (Position{Line: 4, Column: 4}) {
+		t.Errorf("first chair at %v, want the line in the file", got)
+	}
+
+	wantString := `total:
+C: 1, W: 2
+1/kitchen:
+W: 2
+:
+C: 1`
+	if got := parser.String(); got != wantString {
+		t.Errorf("String() =\n%s\nwant:\n%s", got, wantString)
+	}
+
+	wantFloorResults := []FloorResult{
+		{Name: "1", Total: map[string]int{"W": 2, "P": 0, "S": 0, "C": 0}},
+		{Name: "2", Total: map[string]int{"W": 0, "P": 0, "S": 0, "C": 0}},
+		{Name: "3", Total: map[string]int{"W": 0, "P": 0, "S": 0, "C": 1}},
+	}
+	if got := plan.Result().Floors; !reflect.DeepEqual(got, wantFloorResults) {
+		t.Errorf("Result().Floors = %v, want %v", got, wantFloorResults)
+	}
+}
+
+func TestFlatParser_FloorTwice(t *testing.T) {
+	_, err := NewRoomParser().ReadPlan(strings.NewReader("== floor 1 ==\n+--+\n+--+\n== floor 1 =="))
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("ReadPlan() error = %v, want a ParseError", err)
+	}
+	want := ParseError{Position: Position{Line: 4, Column: 1}, Message: "floor 1 is already in the plan"}
+	if *parseErr != want {
+		t.Errorf("ReadPlan() error = %+v, want %+v", *parseErr, want)
+	}
+}
+
+func TestDocument_Floors(t *testing.T) {
+	doc := NewDocument()
+	doc.Update(building)
+	// renaming the last floor reuses everything before it
+	if reused := doc.Update(strings.Replace(building, "floor 3", "floor attic", 1)); reused != 9 {
+		t.Errorf("Update() reused %d lines, want 9", reused)
+	}
+	plan, err := doc.Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := plan.Rooms[1].Floor; got != "attic" {
+		t.Errorf("room on the renamed floor is on floor %q, want attic", got)
+	}
+}
@@ -0,0 +1,148 @@
+package src
+
+import (
+	"bytes"
+	"sort"
+)
+
+// Format renders the plan canonically:
+//   - trailing whitespace and trailing empty lines are dropped,
+//   - every wall junction (corners included) is drawn with a '+',
+//   - every room title is written as "(name)" and centred in its room,
+//   - floor headers are written as "== floor name ==".
+//
+// Formatting never changes what the parser finds in the plan, and formatting an already formatted plan is a no-op.
+func (p *Plan) Format() []byte {
+	grid := make([][]byte, len(p.Lines))
+	// headers are left out of the grid until the walls are joined, a dash in a floor's name is no wall
+	headers := map[int]string{}
+	for i, line := range p.Lines {
+		if name, found := floorHeader(line); found {
+			headers[i] = "== floor " + name + " =="
+			continue
+		}
+		grid[i] = bytes.TrimRight([]byte(line), " \t")
+	}
+
+	joinWalls(grid)
+	for _, room := range p.Rooms {
+		centreTitle(grid, room)
+	}
+	for i, header := range headers {
+		grid[i] = []byte(header)
+	}
+
+	for len(grid) > 0 && len(bytes.TrimSpace(grid[len(grid)-1])) == 0 {
+		grid = grid[:len(grid)-1]
+	}
+	var out bytes.Buffer
+	for _, row := range grid {
+		out.Write(bytes.TrimRight(row, " \t"))
+		out.WriteByte('\n')
+	}
+	return out.Bytes()
+}
+
+// joinWalls turns every straight wall cell that meets both a horizontal and a vertical wall into a '+'.
+// It goes on until nothing changes, since a new '+' can make a junction out of one of its neighbours.
+func joinWalls(grid [][]byte) {
+	at := func(line, column int) byte {
+		if line < 0 || line >= len(grid) || column < 0 || column >= len(grid[line]) {
+			return ' '
+		}
+		return grid[line][column]
+	}
+	horizontal := func(c byte) bool { return c == '-' || c == '+' }
+	vertical := func(c byte) bool { return c == '|' || c == '+' }
+
+	for changed := true; changed; {
+		changed = false
+		for line, row := range grid {
+			for column, c := range row {
+				if c != '-' && c != '|' {
+					continue
+				}
+				if (horizontal(at(line, column-1)) || horizontal(at(line, column+1))) &&
+					(vertical(at(line-1, column)) || vertical(at(line+1, column))) {
+					row[column] = '+'
+					changed = true
+				}
+			}
+		}
+	}
+}
+
+// centreTitle moves the room's title on the middle-most line of the room that has room for it,
+// centred between the walls.
+// If there's no such place, the title stays where it was.
+func centreTitle(grid [][]byte, room *Room) {
+	if room.Title.Length == 0 {
+		return
+	}
+	title := "(" + room.localName() + ")"
+
+	old := room.Title
+	for i := 0; i < old.Length; i++ {
+		grid[old.Line-1][old.Column-1+i] = ' '
+	}
+
+	place, found := titlePlace(grid, room, len(title))
+	if !found {
+		place = old.Position
+	}
+	copy(grid[place.Line-1][place.Column-1:], title)
+}
+
+// titlePlace looks for length free cells in a row of the room,
+// trying the lines closest to the room's middle line first.
+func titlePlace(grid [][]byte, room *Room, length int) (Position, bool) {
+	runs := map[int][]Span{}
+	for _, run := range cellRuns(room.Cells) {
+		runs[run.Line] = append(runs[run.Line], run)
+	}
+
+	lines := make([]int, 0, len(runs))
+	for line := range runs {
+		lines = append(lines, line)
+	}
+	sort.Ints(lines)
+	if len(lines) == 0 {
+		return Position{}, false
+	}
+	middle := (lines[0] + lines[len(lines)-1]) / 2
+	distance := func(line int) int {
+		if line < middle {
+			return middle - line
+		}
+		return line - middle
+	}
+	sort.SliceStable(lines, func(i, j int) bool { return distance(lines[i]) < distance(lines[j]) })
+
+	for _, line := range lines {
+		for _, run := range runs[line] {
+			if run.Length < length {
+				continue
+			}
+			start := run.Column + (run.Length-length)/2
+			// the title shouldn't touch anything but walls and floor, so the chairs next to it stay readable
+			row := grid[line-1]
+			if isBlank(row[start-1:start-1+length]) && isBlankOrWall(row, start-2) && isBlankOrWall(row, start-1+length) {
+				return Position{Line: line, Column: start}, true
+			}
+		}
+	}
+	return Position{}, false
+}
+
+func isBlankOrWall(row []byte, i int) bool {
+	return i < 0 || i >= len(row) || row[i] == ' ' || IsWall(rune(row[i]))
+}
+
+func isBlank(cells []byte) bool {
+	for _, c := range cells {
+		if c != ' ' {
+			return false
+		}
+	}
+	return true
+}
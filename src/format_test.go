@@ -0,0 +1,107 @@
+package src
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPlan_Format(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "trailing whitespace and empty lines",
+			input: "+---+   \n|   |\t\n+---+\n\n\n",
+			want:  "+---+\n|   |\n+---+\n",
+		},
+		{
+			name: "corners and junctions",
+			input: `----|----
+|   |   |
+|---|---|
+|       |
+---------`,
+			want: `+---+---+
+|   |   |
++---+---+
+|       |
++-------+
+`,
+		},
+		{
+			name: "titles are centred",
+			input: `+-----------+
+|(a)        |
+|           |
+|         W |
++-----+-----+
+|  W  |( b )|
++-----+-----+`,
+			want: `+-----------+
+|           |
+|    (a)    |
+|         W |
++-----+-----+
+|  W  | (b) |
++-----+-----+
+`,
+		},
+		{
+			name: "no room for the title in the middle",
+			input: `+-------+
+|P(room)|
+|PPPPPP |
++-------+`,
+			want: `+-------+
+|P(room)|
+|PPPPPP |
++-------+
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := ParsePlan(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ParsePlan() error = %v", err)
+			}
+			if got := string(plan.Format()); got != tt.want {
+				t.Errorf("Format() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlan_Format_Idempotent(t *testing.T) {
+	content, err := os.ReadFile("../rooms.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := ParsePlan(strings.NewReader(string(content)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	once := plan.Format()
+
+	formatted, err := ParsePlan(strings.NewReader(string(once)))
+	if err != nil {
+		t.Fatalf("formatted plan doesn't parse: %v", err)
+	}
+	if twice := formatted.Format(); string(twice) != string(once) {
+		t.Errorf("formatting twice changed the plan:\n%s\nthen:\n%s", once, twice)
+	}
+
+	if len(formatted.Rooms) != len(plan.Rooms) {
+		t.Fatalf("formatted plan has %d rooms, want %d", len(formatted.Rooms), len(plan.Rooms))
+	}
+	for i, room := range plan.Rooms {
+		if got := formatted.Rooms[i]; got.Name != room.Name || len(got.Cells) != len(room.Cells) ||
+			len(got.Placements) != len(room.Placements) {
+			t.Errorf("formatting changed room %q into %q", room.Name, got.Name)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package src
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ReadResult reads back what FlatParser.String writes, the format of the old system's result files:
+// the totals, then every room, each as its name and a colon on one line and its chair counts on the next.
+//
+//	total:
+//	W: 3, P: 2, S: 0, C: 0
+//	kitchen:
+//	W: 3, P: 2
+//	pantry
+//
+// A room without chairs is its name alone, without a colon, or "(no data)" if it has no name either.
+// Counts can come in any order, zeros may be left out, but a type can't be given twice.
+// The totals must be the sum of the rooms, or the file is not to be trusted.
+//
+// Since a name is only told apart from counts by its colon, the name of a room without chairs can't end with one.
+// The rooms of building plans are read back with their floor in their names, like 2/kitchen.
+func ReadResult(reader io.Reader) (*Result, error) {
+	var lines []string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimSuffix(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("can't read the result: %w", err)
+	}
+
+	var entries []RoomResult
+	for i := 0; i < len(lines); i++ {
+		line := i + 1
+		name, hasChairs := strings.CutSuffix(lines[i], ":")
+		if !hasChairs {
+			if lines[i] == "" {
+				return nil, fmt.Errorf("[result line %d] empty line, want a room name", line)
+			}
+			if lines[i] == "(no data)" {
+				name = ""
+			}
+			entries = append(entries, RoomResult{Name: name, Chairs: catalogCounts(nil)})
+			continue
+		}
+		if i+1 == len(lines) {
+			return nil, fmt.Errorf("[result line %d] %s has no chair counts after it", line, lines[i])
+		}
+		i++
+		chairs, err := parseCounts(lines[i])
+		if err != nil {
+			return nil, fmt.Errorf("[result line %d] %w", i+1, err)
+		}
+		entries = append(entries, RoomResult{Name: name, Chairs: chairs})
+	}
+
+	if len(entries) == 0 || entries[0].Name != "total" {
+		return nil, fmt.Errorf("the result doesn't start with the totals")
+	}
+	result := &Result{Total: entries[0].Chairs, Rooms: entries[1:]}
+	sum := catalogCounts(nil)
+	for _, room := range result.Rooms {
+		for code, count := range room.Chairs {
+			sum[code] += count
+		}
+	}
+	for _, chairType := range ChairTypes {
+		code := string(chairType.Code)
+		if sum[code] != result.Total[code] {
+			return nil, fmt.Errorf("the totals don't add up: %s: totals %d, rooms %d", code, result.Total[code], sum[code])
+		}
+	}
+	return result, nil
+}
+
+// parseCounts parses chair counts the way ChairCounts and FlatParser.String write them: "W: 3, P: 2".
+// Every type of the catalog is in the result, the ones left out with a zero count.
+func parseCounts(line string) (map[string]int, error) {
+	counts := catalogCounts(nil)
+	seen := map[string]bool{}
+	for _, pair := range strings.Split(line, ", ") {
+		code, number, found := strings.Cut(pair, ": ")
+		if !found {
+			return nil, fmt.Errorf("%q is not a chair count like W: 3", pair)
+		}
+		if len([]rune(code)) != 1 || !IsChair([]rune(code)[0]) {
+			return nil, fmt.Errorf("%q is not a chair type", code)
+		}
+		if seen[code] {
+			return nil, fmt.Errorf("%s counted twice", code)
+		}
+		seen[code] = true
+		count, err := strconv.Atoi(number)
+		if err != nil || count < 0 || strings.TrimLeft(number, "0123456789") != "" {
+			return nil, fmt.Errorf("%q is not a count of chairs", number)
+		}
+		counts[code] = count
+	}
+	return counts, nil
+}
+
+// Plan turns the result into a plan with the rooms' names and chairs and nothing else,
+// for comparing with DiffPlans. The rooms get sorted by name, like a parsed plan's.
+func (r *Result) Plan() *Plan {
+	plan := &Plan{}
+	for _, roomResult := range r.Rooms {
+		room := &Room{Name: roomResult.Name, Chairs: map[rune]int{}}
+		for code, count := range roomResult.Chairs {
+			if count > 0 {
+				room.Chairs[[]rune(code)[0]] = count
+			}
+		}
+		plan.Rooms = append(plan.Rooms, room)
+	}
+	sort.SliceStable(plan.Rooms, func(i, j int) bool { return plan.Rooms[i].Name < plan.Rooms[j].Name })
+	return plan
+}
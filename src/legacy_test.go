@@ -0,0 +1,109 @@
+package src
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadResult_RoundTrip(t *testing.T) {
+	for name, input := range map[string]string{
+		"rooms": `+-----+-------+
+|(a) W|(b)    |
+|   W +---+ P |
++-----+   |   |
+      +---+---+`,
+		"no chairs": "+---+\n|(a)|\n+---+",
+		"no rooms":  "",
+		"building":  building,
+	} {
+		t.Run(name, func(t *testing.T) {
+			parser := NewRoomParser()
+			plan, err := parser.ReadPlan(strings.NewReader(input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReadResult(strings.NewReader(parser.String()))
+			if err != nil {
+				t.Fatalf("ReadResult(%q) error = %v", parser.String(), err)
+			}
+			// the old format has neither the areas, the placements nor the floor totals
+			want := &Result{Total: plan.Result().Total, Rooms: plan.Result().Rooms}
+			for i, room := range want.Rooms {
+				want.Rooms[i] = RoomResult{Name: room.Name, Chairs: room.Chairs}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ReadResult(%q) = %+v, want %+v", parser.String(), got, want)
+			}
+		})
+	}
+}
+
+func TestReadResult_DiffBuilding(t *testing.T) {
+	parser := NewRoomParser()
+	plan, err := parser.ReadPlan(strings.NewReader(building + "\n== floor 4 ==\n+----+\n|(a) |\n|  W |\n+----+"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := ReadResult(strings.NewReader(parser.String()))
+	if err != nil {
+		t.Fatalf("ReadResult(%q) error = %v", parser.String(), err)
+	}
+	// the unnamed room can't be matched by name, the others must all be the same
+	diff := DiffPlans(result.Plan(), plan)
+	for _, change := range diff.Rooms {
+		if change.Old != "" || change.New != "" {
+			t.Errorf("DiffPlans() changed %+v, want only the unnamed room", change)
+		}
+	}
+	for code, delta := range diff.Totals {
+		if delta != 0 {
+			t.Errorf("DiffPlans() changed the totals of %s by %d, want none", code, delta)
+		}
+	}
+}
+
+func TestReadResult(t *testing.T) {
+	result, err := ReadResult(strings.NewReader("total:\r\nW: 3, P: 2, S: 0, C: 0\r\nkitchen:\r\nP: 2, W: 3\r\n(no data)\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Result{
+		Total: map[string]int{"W": 3, "P": 2, "S": 0, "C": 0},
+		Rooms: []RoomResult{
+			{Name: "kitchen", Chairs: map[string]int{"W": 3, "P": 2, "S": 0, "C": 0}},
+			{Name: "", Chairs: map[string]int{"W": 0, "P": 0, "S": 0, "C": 0}},
+		},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("ReadResult() = %+v, want %+v", result, want)
+	}
+	// results have no cells to match the rooms by, only names
+	diff := DiffPlans(result.Plan(), (&Result{Rooms: want.Rooms[:1]}).Plan())
+	if want := "removed (no name)\ntotal: W: +0, P: +0, S: +0, C: +0"; diff.String() != want {
+		t.Errorf("DiffPlans() = %s, want %s", diff, want)
+	}
+
+	for _, tt := range []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty", "", "the result doesn't start with the totals"},
+		{"no totals", "kitchen:\nW: 1", "the result doesn't start with the totals"},
+		{"missing counts", "total:\nW: 1\nkitchen:", "[result line 3] kitchen: has no chair counts after it"},
+		{"unknown type", "total:\nX: 1", `[result line 2] "X" is not a chair type`},
+		{"twice", "total:\nW: 1, W: 2", "[result line 2] W counted twice"},
+		{"negative", "total:\nW: -1", `[result line 2] "-1" is not a count of chairs`},
+		{"not a count", "total:\nW 1", `[result line 2] "W 1" is not a chair count like W: 3`},
+		{"empty line", "total\n\nkitchen", "[result line 2] empty line, want a room name"},
+		{"wrong totals", "total:\nW: 2\nkitchen:\nW: 1, S: 1", "the totals don't add up: W: totals 2, rooms 1"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadResult(strings.NewReader(tt.input))
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("ReadResult() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+)
+
+// JSON-RPC error codes used by the server.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// message is any JSON-RPC message: a request has an ID and a Method, a notification only a Method.
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result"`
+}
+
+type errorResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Error   *responseError  `json:"error"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *responseError) Error() string {
+	return e.Message
+}
+
+type notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+// frameError is a message whose headers make no sense: the reader is past them, and can go on with the next message.
+type frameError struct {
+	err error
+}
+
+func (e *frameError) Error() string {
+	return "bad message headers: " + e.err.Error()
+}
+
+func (e *frameError) Unwrap() error {
+	return e.err
+}
+
+// readMessage reads the next message, framed the way LSP frames them:
+// headers, the Content-Length one being the only one that matters, then the JSON content.
+// Headers that are malformed, or lack a sound Content-Length, are a *frameError.
+func readMessage(reader *bufio.Reader) ([]byte, error) {
+	textReader := textproto.NewReader(reader)
+	headers, err := textReader.ReadMIMEHeader()
+	var protocolErr textproto.ProtocolError
+	if errors.As(err, &protocolErr) {
+		// skip the rest of the headers, up to the blank line after them
+		for {
+			line, err := textReader.ReadLine()
+			if err != nil {
+				return nil, err
+			}
+			if line == "" {
+				return nil, &frameError{protocolErr}
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	length, err := strconv.Atoi(headers.Get("Content-Length"))
+	if err != nil || length < 0 {
+		return nil, &frameError{fmt.Errorf("bad Content-Length header: %q", headers.Get("Content-Length"))}
+	}
+	content := make([]byte, length)
+	if _, err := io.ReadFull(reader, content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// writeMessage frames v the way readMessage expects it.
+func writeMessage(w io.Writer, v any) error {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "Content-Length: %d\r\n\r\n", len(content)); err != nil {
+		return err
+	}
+	_, err = w.Write(content)
+	return err
+}
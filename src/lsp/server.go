@@ -0,0 +1,300 @@
+// Package lsp is a language server for plan files:
+// it publishes the parser's diagnostics, and answers hovers and document symbol requests with the rooms it found.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"enspired/src"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Server keeps the documents the client has open, each as a src.Document,
+// so an edit only gets the lines from the edited one onward parsed again.
+type Server struct {
+	out       io.Writer
+	documents map[string]*src.Document
+	shutdown  bool
+}
+
+// Serve answers the LSP messages read from in on out, one at a time,
+// until the client sends exit or in runs out. A message with bad headers is logged to the client and skipped.
+// Exiting without a shutdown request first is an error, as the specification wants it.
+func Serve(in io.Reader, out io.Writer) error {
+	s := &Server{out: out, documents: map[string]*src.Document{}}
+	reader := bufio.NewReader(in)
+	for {
+		content, err := readMessage(reader)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		var frameErr *frameError
+		if errors.As(err, &frameErr) {
+			if err := s.notify("window/logMessage", LogMessageParams{Type: messageTypeError, Message: frameErr.Error()}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		var msg message
+		if err := json.Unmarshal(content, &msg); err != nil {
+			if err := s.reply(json.RawMessage("null"), nil, &responseError{Code: codeParseError, Message: err.Error()}); err != nil {
+				return err
+			}
+			continue
+		}
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("exit before shutdown")
+			}
+			return nil
+		}
+
+		result, err := s.handle(msg)
+		var rpcErr *responseError
+		if err != nil && !errors.As(err, &rpcErr) {
+			return err
+		}
+		if msg.ID == nil {
+			// notifications don't get answers, not even errors
+			continue
+		}
+		if err := s.reply(msg.ID, result, rpcErr); err != nil {
+			return err
+		}
+	}
+}
+
+// handle runs a request or notification.
+// The errors meant for the client are *responseError, any other one is the server failing to write.
+func (s *Server) handle(msg message) (any, error) {
+	if s.shutdown {
+		return nil, &responseError{Code: codeInvalidRequest, Message: "the server is shutting down"}
+	}
+
+	switch msg.Method {
+	case "initialize":
+		return InitializeResult{
+			Capabilities: ServerCapabilities{
+				TextDocumentSync:       textDocumentSyncFull,
+				HoverProvider:          true,
+				DocumentSymbolProvider: true,
+			},
+			ServerInfo: ServerInfo{Name: "enspired"},
+		}, nil
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err := decodeParams(msg, &params); err != nil {
+			return nil, err
+		}
+		document := src.NewDocument()
+		document.Update(params.TextDocument.Text)
+		s.documents[params.TextDocument.URI] = document
+		return nil, s.publishDiagnostics(params.TextDocument.URI)
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err := decodeParams(msg, &params); err != nil {
+			return nil, err
+		}
+		document, ok := s.documents[params.TextDocument.URI]
+		if !ok {
+			document = src.NewDocument()
+			s.documents[params.TextDocument.URI] = document
+		}
+		if len(params.ContentChanges) > 0 {
+			document.Update(params.ContentChanges[len(params.ContentChanges)-1].Text)
+		}
+		return nil, s.publishDiagnostics(params.TextDocument.URI)
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err := decodeParams(msg, &params); err != nil {
+			return nil, err
+		}
+		delete(s.documents, params.TextDocument.URI)
+		return nil, s.notify("textDocument/publishDiagnostics",
+			PublishDiagnosticsParams{URI: params.TextDocument.URI, Diagnostics: []Diagnostic{}})
+
+	case "textDocument/hover":
+		var params TextDocumentPositionParams
+		if err := decodeParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return s.hover(params)
+	case "textDocument/documentSymbol":
+		var params DocumentSymbolParams
+		if err := decodeParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return s.documentSymbols(params)
+	}
+
+	if msg.ID == nil {
+		return nil, nil
+	}
+	return nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not found: %s", msg.Method)}
+}
+
+// publishDiagnostics sends what's wrong with the document: the error the parser gave up on,
+// or else the rooms that never close.
+func (s *Server) publishDiagnostics(uri string) error {
+	plan, err := s.documents[uri].Plan()
+	problems := plan.Problems()
+	if err != nil {
+		problems = []*src.ParseError{src.AsParseError(err)}
+	}
+
+	diagnostics := []Diagnostic{}
+	for _, problem := range problems {
+		diagnostic := Diagnostic{Severity: severityError, Source: "enspired", Message: problem.Message}
+		if problem.Position != (src.Position{}) {
+			diagnostic.Range = cellRange(plan.Lines, problem.Position)
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{URI: uri, Diagnostics: diagnostics})
+}
+
+// hover tells which room the cell under the cursor belongs to, and the chairs in it.
+// There's nothing to tell about walls and what's outside the rooms.
+func (s *Server) hover(params TextDocumentPositionParams) (*Hover, error) {
+	if params.Position.Line < 0 || params.Position.Character < 0 {
+		return nil, &responseError{Code: codeInvalidParams, Message: fmt.Sprintf("negative position: %+v", params.Position)}
+	}
+	plan, err := s.plan(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	pos := planPosition(plan.Lines, params.Position)
+	room, kind := plan.RoomAt(pos)
+	if kind != src.Floor {
+		return nil, nil
+	}
+	return &Hover{
+		Contents: MarkupContent{
+			Kind: "markdown",
+			Value: fmt.Sprintf("**%s**\n\n%s\n\n%d cells",
+				displayName(room.Name), src.ChairCounts(room.Chairs), len(room.Cells)),
+		},
+		Range: cellRange(plan.Lines, pos),
+	}, nil
+}
+
+// documentSymbols lists the rooms, each spanning from its first cell to its last one, and selected at its title.
+func (s *Server) documentSymbols(params DocumentSymbolParams) ([]DocumentSymbol, error) {
+	plan, err := s.plan(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	symbols := []DocumentSymbol{}
+	for _, room := range plan.Rooms {
+		if len(room.Cells) == 0 {
+			continue
+		}
+		first, last := room.Cells[0], room.Cells[len(room.Cells)-1]
+		symbol := DocumentSymbol{
+			Name:   displayName(room.Name),
+			Detail: src.ChairCounts(room.Chairs),
+			Kind:   symbolKindStruct,
+			Range: Range{
+				Start: lspPosition(plan.Lines, first),
+				End:   cellRange(plan.Lines, last).End,
+			},
+			SelectionRange: cellRange(plan.Lines, first),
+		}
+		if room.Title.Length > 0 {
+			symbol.SelectionRange = Range{
+				Start: lspPosition(plan.Lines, room.Title.Position),
+				End: lspPosition(plan.Lines,
+					src.Position{Line: room.Title.Line, Column: room.Title.Column + room.Title.Length}),
+			}
+		}
+		symbols = append(symbols, symbol)
+	}
+	return symbols, nil
+}
+
+func (s *Server) plan(uri string) (*src.Plan, error) {
+	document, ok := s.documents[uri]
+	if !ok {
+		return nil, &responseError{Code: codeInvalidParams, Message: fmt.Sprintf("document not open: %s", uri)}
+	}
+	plan, _ := document.Plan()
+	return plan, nil
+}
+
+func (s *Server) reply(id json.RawMessage, result any, rpcErr *responseError) error {
+	if rpcErr != nil {
+		return writeMessage(s.out, errorResponse{JSONRPC: "2.0", ID: id, Error: rpcErr})
+	}
+	return writeMessage(s.out, response{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+func (s *Server) notify(method string, params any) error {
+	return writeMessage(s.out, notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func decodeParams(msg message, params any) error {
+	if err := json.Unmarshal(msg.Params, params); err != nil {
+		return &responseError{Code: codeInvalidParams, Message: fmt.Sprintf("bad %s params: %v", msg.Method, err)}
+	}
+	return nil
+}
+
+func displayName(name string) string {
+	if name == "" {
+		return "(no name)"
+	}
+	return name
+}
+
+// lspPosition converts a position of the plan, whose columns count bytes from 1, to an LSP one.
+// Columns beyond the end of the line count as spaces.
+func lspPosition(lines []string, pos src.Position) Position {
+	lspPos := Position{Line: pos.Line - 1, Character: pos.Column - 1}
+	if lspPos.Line < 0 || lspPos.Line >= len(lines) {
+		return lspPos
+	}
+	line := lines[lspPos.Line]
+	end := min(max(pos.Column-1, 0), len(line))
+	lspPos.Character = len(utf16.Encode([]rune(line[:end]))) + pos.Column - 1 - end
+	return lspPos
+}
+
+// planPosition converts an LSP position to a position of the plan.
+// Positions before the start of the plan, which no client should send, are taken as they are.
+func planPosition(lines []string, pos Position) src.Position {
+	column, units := 0, 0
+	if pos.Line >= 0 && pos.Line < len(lines) {
+		line := lines[pos.Line]
+		for column < len(line) && units < pos.Character {
+			r, size := utf8.DecodeRuneInString(line[column:])
+			units += len(utf16.Encode([]rune{r}))
+			column += size
+		}
+	}
+	return src.Position{Line: pos.Line + 1, Column: column + pos.Character - units + 1}
+}
+
+// cellRange is the range of the single character at pos.
+func cellRange(lines []string, pos src.Position) Range {
+	end := pos
+	end.Column++
+	if pos.Line >= 1 && pos.Line <= len(lines) && pos.Column >= 1 && pos.Column <= len(lines[pos.Line-1]) {
+		_, size := utf8.DecodeRuneInString(lines[pos.Line-1][pos.Column-1:])
+		end.Column = pos.Column + size
+	}
+	return Range{Start: lspPosition(lines, pos), End: lspPosition(lines, end)}
+}
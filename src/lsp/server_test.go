@@ -0,0 +1,210 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"enspired/src"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// client plays the editor's side of the conversation, one message at a time.
+type client struct {
+	t      *testing.T
+	in     io.Writer
+	out    *bufio.Reader
+	served chan error
+}
+
+func newClient(t *testing.T) *client {
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+	c := &client{t: t, in: clientOut, out: bufio.NewReader(clientIn), served: make(chan error, 1)}
+	go func() {
+		c.served <- Serve(serverIn, serverOut)
+		_ = serverOut.Close()
+	}()
+	return c
+}
+
+func (c *client) send(msg string) {
+	c.t.Helper()
+	if err := writeMessage(c.in, json.RawMessage(msg)); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// expectLog reads the server's next message, which must log an error.
+func (c *client) expectLog() {
+	c.t.Helper()
+	content, err := readMessage(c.out)
+	if err != nil {
+		c.t.Fatalf("reading the server's message: %v", err)
+	}
+	var got struct {
+		Method string           `json:"method"`
+		Params LogMessageParams `json:"params"`
+	}
+	if err := json.Unmarshal(content, &got); err != nil {
+		c.t.Fatal(err)
+	}
+	if got.Method != "window/logMessage" || got.Params.Type != messageTypeError || got.Params.Message == "" {
+		c.t.Errorf("server sent:\n%s\nwant an error logged", content)
+	}
+}
+
+// expect reads the server's next message and compares it to want, both as JSON values.
+func (c *client) expect(want string) {
+	c.t.Helper()
+	content, err := readMessage(c.out)
+	if err != nil {
+		c.t.Fatalf("reading the server's message: %v", err)
+	}
+	var got, wantValue any
+	if err := json.Unmarshal(content, &got); err != nil {
+		c.t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		c.t.Fatalf("bad expectation %s: %v", want, err)
+	}
+	if !reflect.DeepEqual(got, wantValue) {
+		c.t.Errorf("server sent:\n%s\nwant:\n%s", content, want)
+	}
+}
+
+func TestServe(t *testing.T) {
+	c := newClient(t)
+
+	c.send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"capabilities":{}}}`)
+	c.expect(`{"jsonrpc":"2.0","id":1,"result":{"capabilities":{"textDocumentSync":1,"hoverProvider":true,"documentSymbolProvider":true},"serverInfo":{"name":"enspired"}}}`)
+	c.send(`{"jsonrpc":"2.0","method":"initialized","params":{}}`)
+
+	c.send(`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///plan.txt","languageId":"plaintext","version":1,
+		"text":"+------+---+\n|(a) W |  C|\n+------+---+\n"}}}`)
+	c.expect(`{"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///plan.txt","diagnostics":[]}}`)
+
+	c.send(`{"jsonrpc":"2.0","id":2,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///plan.txt"},"position":{"line":1,"character":5}}}`)
+	c.expect(`{"jsonrpc":"2.0","id":2,"result":{
+		"contents":{"kind":"markdown","value":"**a**\n\nW: 1, P: 0, S: 0, C: 0\n\n6 cells"},
+		"range":{"start":{"line":1,"character":5},"end":{"line":1,"character":6}}}}`)
+
+	c.send(`{"jsonrpc":"2.0","id":3,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///plan.txt"},"position":{"line":1,"character":7}}}`)
+	c.expect(`{"jsonrpc":"2.0","id":3,"result":null}`)
+
+	c.send(`{"jsonrpc":"2.0","id":4,"method":"textDocument/documentSymbol","params":{"textDocument":{"uri":"file:///plan.txt"}}}`)
+	c.expect(`{"jsonrpc":"2.0","id":4,"result":[
+		{"name":"(no name)","detail":"W: 0, P: 0, S: 0, C: 1","kind":23,
+			"range":{"start":{"line":1,"character":8},"end":{"line":1,"character":11}},
+			"selectionRange":{"start":{"line":1,"character":8},"end":{"line":1,"character":9}}},
+		{"name":"a","detail":"W: 1, P: 0, S: 0, C: 0","kind":23,
+			"range":{"start":{"line":1,"character":1},"end":{"line":1,"character":7}},
+			"selectionRange":{"start":{"line":1,"character":1},"end":{"line":1,"character":4}}}]}`)
+
+	c.send(`{"jsonrpc":"2.0","method":"textDocument/didChange","params":{"textDocument":{"uri":"file:///plan.txt","version":2},
+		"contentChanges":[{"text":"+------+---+\n|(a) X |  C|\n+------+---+\n"}]}}`)
+	c.expect(`{"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///plan.txt","diagnostics":[
+		{"range":{"start":{"line":1,"character":5},"end":{"line":1,"character":6}},"severity":1,"source":"enspired","message":"strange character encountered: X"}]}}`)
+
+	c.send(`{"jsonrpc":"2.0","method":"textDocument/didChange","params":{"textDocument":{"uri":"file:///plan.txt","version":3},
+		"contentChanges":[{"text":"+------+\n|(a) W |\n"}]}}`)
+	c.expect(`{"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///plan.txt","diagnostics":[
+		{"range":{"start":{"line":1,"character":1},"end":{"line":1,"character":2}},"severity":1,"source":"enspired","message":"room a never closes"}]}}`)
+
+	c.send(`{"jsonrpc":"2.0","id":5,"method":"textDocument/completion","params":{"textDocument":{"uri":"file:///plan.txt"},"position":{"line":0,"character":0}}}`)
+	c.expect(`{"jsonrpc":"2.0","id":5,"error":{"code":-32601,"message":"method not found: textDocument/completion"}}`)
+
+	c.send(`{"jsonrpc":"2.0","method":"textDocument/didClose","params":{"textDocument":{"uri":"file:///plan.txt"}}}`)
+	c.expect(`{"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///plan.txt","diagnostics":[]}}`)
+
+	c.send(`{"jsonrpc":"2.0","id":6,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///plan.txt"},"position":{"line":1,"character":5}}}`)
+	c.expect(`{"jsonrpc":"2.0","id":6,"error":{"code":-32602,"message":"document not open: file:///plan.txt"}}`)
+
+	c.send(`{"jsonrpc":"2.0","id":7,"method":"shutdown"}`)
+	c.expect(`{"jsonrpc":"2.0","id":7,"result":null}`)
+	c.send(`{"jsonrpc":"2.0","method":"exit"}`)
+	if err := <-c.served; err != nil {
+		t.Errorf("Serve() error = %v", err)
+	}
+}
+
+func TestServe_ExitBeforeShutdown(t *testing.T) {
+	c := newClient(t)
+	c.send(`{"jsonrpc":"2.0","method":"exit"}`)
+	if err := <-c.served; err == nil {
+		t.Error("Serve() didn't complain about exiting before shutdown")
+	}
+}
+
+func TestServe_BadHeaders(t *testing.T) {
+	c := newClient(t)
+	for _, headers := range []string{"Content-Length: many\r\n\r\n", "Content-Type: text/plain\r\n\r\n", "no header at all\r\n\r\n"} {
+		if _, err := io.WriteString(c.in, headers); err != nil {
+			t.Fatal(err)
+		}
+		c.expectLog()
+	}
+	// the server goes on with the next message
+	c.send(`{"jsonrpc":"2.0","id":1,"method":"shutdown"}`)
+	c.expect(`{"jsonrpc":"2.0","id":1,"result":null}`)
+	c.send(`{"jsonrpc":"2.0","method":"exit"}`)
+	if err := <-c.served; err != nil {
+		t.Errorf("Serve() error = %v", err)
+	}
+}
+
+func TestServe_NegativePosition(t *testing.T) {
+	c := newClient(t)
+	c.send(`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///plan.txt","languageId":"plaintext","version":1,
+		"text":"+------+\n|(a) W |\n+------+\n"}}}`)
+	c.expect(`{"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///plan.txt","diagnostics":[]}}`)
+
+	c.send(`{"jsonrpc":"2.0","id":1,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///plan.txt"},"position":{"line":-1,"character":2}}}`)
+	c.expect(`{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"negative position: {Line:-1 Character:2}"}}`)
+	c.send(`{"jsonrpc":"2.0","id":2,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///plan.txt"},"position":{"line":1,"character":-5}}}`)
+	c.expect(`{"jsonrpc":"2.0","id":2,"error":{"code":-32602,"message":"negative position: {Line:1 Character:-5}"}}`)
+
+	c.send(`{"jsonrpc":"2.0","id":3,"method":"shutdown"}`)
+	c.expect(`{"jsonrpc":"2.0","id":3,"result":null}`)
+	c.send(`{"jsonrpc":"2.0","method":"exit"}`)
+	if err := <-c.served; err != nil {
+		t.Errorf("Serve() error = %v", err)
+	}
+}
+
+func TestPositions(t *testing.T) {
+	// 'ü' takes 2 bytes and 1 UTF-16 unit, '𝄞' 4 bytes and 2 units
+	lines := []string{"|(grün) 𝄞 W|"}
+
+	tests := []struct {
+		plan src.Position
+		lsp  Position
+	}{
+		{plan: src.Position{Line: 1, Column: 1}, lsp: Position{Line: 0, Character: 0}},
+		{plan: src.Position{Line: 1, Column: 5}, lsp: Position{Line: 0, Character: 4}},
+		{plan: src.Position{Line: 1, Column: 7}, lsp: Position{Line: 0, Character: 5}},
+		{plan: src.Position{Line: 1, Column: 10}, lsp: Position{Line: 0, Character: 8}},
+		{plan: src.Position{Line: 1, Column: 14}, lsp: Position{Line: 0, Character: 10}},
+		{plan: src.Position{Line: 1, Column: 18}, lsp: Position{Line: 0, Character: 14}},
+		{plan: src.Position{Line: 3, Column: 2}, lsp: Position{Line: 2, Character: 1}},
+	}
+	for _, tt := range tests {
+		if got := lspPosition(lines, tt.plan); got != tt.lsp {
+			t.Errorf("lspPosition(%v) = %v, want %v", tt.plan, got, tt.lsp)
+		}
+		if got := planPosition(lines, tt.lsp); got != tt.plan {
+			t.Errorf("planPosition(%v) = %v, want %v", tt.lsp, got, tt.plan)
+		}
+	}
+
+	// before the start of the plan, they are only taken as they are
+	if got, want := lspPosition(lines, src.Position{Line: 1, Column: -2}), (Position{Line: 0, Character: -3}); got != want {
+		t.Errorf("lspPosition() = %v, want %v", got, want)
+	}
+	if got, want := planPosition(lines, Position{Line: -1, Character: 2}), (src.Position{Line: 0, Column: 3}); got != want {
+		t.Errorf("planPosition() = %v, want %v", got, want)
+	}
+	if got, want := cellRange(lines, src.Position{Line: 0, Column: -1}).End, (Position{Line: -1, Character: -1}); got != want {
+		t.Errorf("cellRange().End = %v, want %v", got, want)
+	}
+}
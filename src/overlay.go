@@ -0,0 +1,59 @@
+package src
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// overlayMarks are the characters the overlay draws the cells of the rooms with: the i-th room gets the i-th mark.
+// Lowercase only, so they can't be mistaken for chairs.
+const overlayMarks = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// ANSI background colours the overlay cycles through when colouring rooms
+var overlayColours = []int{41, 42, 43, 44, 45, 46, 101, 102, 103, 104, 105, 106}
+
+// Overlay reprints the plan with every cell of every room replaced by the room's mark,
+// then lists which room each mark stands for.
+// With colour on, each room also gets an ANSI background colour of its own.
+//
+// Cells that don't belong to any closed room are left as drawn,
+// so rooms that got merged or split by mistake are easy to spot.
+func (p *Plan) Overlay(writer io.Writer, colour bool) error {
+	w := bufio.NewWriter(writer)
+	cellRooms := p.cellRooms()
+
+	for l, line := range p.Lines {
+		for c := 0; c < len(line); c++ {
+			i, found := cellRooms[Position{Line: l + 1, Column: c + 1}]
+			if !found {
+				w.WriteByte(line[c])
+				continue
+			}
+			if colour {
+				fmt.Fprintf(w, "\x1b[30;%dm%c\x1b[0m", overlayColours[i%len(overlayColours)], overlayMark(i))
+				continue
+			}
+			w.WriteByte(overlayMark(i))
+		}
+		w.WriteByte('\n')
+	}
+
+	for i, room := range p.Rooms {
+		name := room.Name
+		if name == "" {
+			name = "(no name)"
+		}
+		mark := string(overlayMark(i))
+		if colour {
+			mark = fmt.Sprintf("\x1b[30;%dm%s\x1b[0m", overlayColours[i%len(overlayColours)], mark)
+		}
+		fmt.Fprintf(w, "%s: %s\n", mark, name)
+	}
+
+	return w.Flush()
+}
+
+func overlayMark(i int) byte {
+	return overlayMarks[i%len(overlayMarks)]
+}
@@ -0,0 +1,57 @@
+package src
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlan_Overlay(t *testing.T) {
+	colouredA := "\x1b[30;41ma\x1b[0m"
+
+	tests := []struct {
+		name   string
+		input  string
+		colour bool
+		want   string
+	}{
+		{
+			name: "plain",
+			input: `+-----+-------+
+|(a) W|(b)    |
+|   W +---+ P |
++-----+   |   |
+      +---+---+`,
+			want: `+-----+-------+
+|bbbbb|ccccccc|
+|bbbbb+---+ccc|
++-----+aaa|ccc|
+      +---+---+
+a: (no name)
+b: a
+c: b
+`,
+		},
+		{
+			name:   "colour",
+			input:  "+---+\n|(x)|\n+---+",
+			colour: true,
+			want:   "+---+\n|" + strings.Repeat(colouredA, 3) + "|\n+---+\n" + colouredA + ": x\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := ParsePlan(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out strings.Builder
+			if err := plan.Overlay(&out, tt.colour); err != nil {
+				t.Fatalf("Overlay() error = %v", err)
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("Overlay() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package src
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Plan is a whole floor plan held in memory:
+// the drawing as a grid of cells, plus the rooms the parser found in it.
+type Plan struct {
+	// Lines are the lines of the drawing, without line endings.
+	Lines []string
+	// Rooms are the closed rooms, sorted by name.
+	Rooms []*Room
+	// Unclosed are the rooms that were still open when the plan, or their floor, ended: their walls have a gap somewhere.
+	Unclosed []*Room
+	// Floors are the floors of a building plan, in the order they're drawn. Plans without floor headers have none.
+	Floors []Storey
+
+	// the index in Rooms of the room each floor cell belongs to, built once on the first lookup,
+	// whatever goroutine does it
+	cells     map[Position]int
+	cellsOnce sync.Once
+}
+
+// CellKind tells what's drawn in a cell of the plan.
+type CellKind int
+
+const (
+	// Outside is anything that's neither a wall nor the floor of a closed room:
+	// outside the building, beyond the drawing, or in a room that never closed.
+	Outside CellKind = iota
+	Wall
+	Floor
+)
+
+func (k CellKind) String() string {
+	switch k {
+	case Wall:
+		return "wall"
+	case Floor:
+		return "floor"
+	}
+	return "outside"
+}
+
+// ParsePlan reads the whole plan from reader and runs it through a new FlatParser.
+func ParsePlan(reader io.Reader) (*Plan, error) {
+	return NewRoomParser().ReadPlan(reader)
+}
+
+// ReadPlan reads the whole plan from reader and ingests it line by line.
+// Unlike IngestAllFromReader, it keeps the last line even when it has no trailing newline.
+func (p *FlatParser) ReadPlan(reader io.Reader) (*Plan, error) {
+	plan := &Plan{}
+
+	buffered := bufio.NewReader(reader)
+	for {
+		line, err := buffered.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if line != "" {
+			line = strings.TrimRight(line, "\r\n")
+			plan.Lines = append(plan.Lines, line)
+			if err := p.Ingest(line); err != nil {
+				return nil, fmt.Errorf("[line %d] error parsing line: %w", p.Line, err)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+
+	found := p.Plan()
+	plan.Rooms, plan.Unclosed, plan.Floors = found.Rooms, found.Unclosed, found.Floors
+	return plan, nil
+}
+
+// Plan returns what the parser found in the lines it ingested so far.
+// The parser doesn't keep the lines themselves, so the plan has none.
+func (p *FlatParser) Plan() *Plan {
+	plan := &Plan{Rooms: p.Rooms(), Floors: slices.Clone(p.floors)}
+	for _, room := range p.unclosed {
+		plan.Unclosed = append(plan.Unclosed, room.room())
+	}
+	for _, room := range p.OpenRooms {
+		plan.Unclosed = append(plan.Unclosed, room.RoomData.room())
+	}
+	return plan
+}
+
+// Totals counts the chairs of all the plan's rooms, by type.
+func (p *Plan) Totals() map[rune]int {
+	return roomTotals(p.Rooms)
+}
+
+// At returns the character drawn at pos, or a space if pos is outside the drawing.
+func (p *Plan) At(pos Position) rune {
+	if pos.Line < 1 || pos.Line > len(p.Lines) {
+		return ' '
+	}
+	line := p.Lines[pos.Line-1]
+	if pos.Column < 1 || pos.Column > len(line) {
+		return ' '
+	}
+	return rune(line[pos.Column-1])
+}
+
+// RoomAt tells which room the cell at pos belongs to.
+// The room is nil unless the cell is Floor.
+func (p *Plan) RoomAt(pos Position) (*Room, CellKind) {
+	if i, found := p.cellRooms()[pos]; found {
+		return p.Rooms[i], Floor
+	}
+	if IsWall(p.At(pos)) {
+		return nil, Wall
+	}
+	return nil, Outside
+}
+
+// cellRooms maps every floor cell of the plan to the index of its room in p.Rooms.
+func (p *Plan) cellRooms() map[Position]int {
+	p.cellsOnce.Do(func() {
+		p.cells = map[Position]int{}
+		for i, room := range p.Rooms {
+			for _, cell := range room.Cells {
+				p.cells[cell] = i
+			}
+		}
+	})
+	return p.cells
+}
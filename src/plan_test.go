@@ -0,0 +1,171 @@
+package src
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestParsePlan(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantLines int
+		wantRooms []*Room
+		wantErr   bool
+	}{
+		{
+			name: "geometry of a single room",
+			input: `+------+
+| W (a)|
+|   P  |
++------+`,
+			wantLines: 4,
+			wantRooms: []*Room{
+				{
+					Name:   "a",
+					Chairs: map[rune]int{'W': 1, 'P': 1},
+					Cells: []Position{
+						{2, 2}, {2, 3}, {2, 4}, {2, 5}, {2, 6}, {2, 7},
+						{3, 2}, {3, 3}, {3, 4}, {3, 5}, {3, 6}, {3, 7},
+					},
+					Placements: []Chair{{'W', Position{2, 3}}, {'P', Position{3, 5}}},
+					Title:      Span{Position{2, 5}, 3},
+				},
+			},
+		},
+		{
+			name: "segments joining back are counted once",
+			input: `+-------+
+|       |
+| | | | |
+|   P   |
++-------+
+`,
+			wantLines: 5,
+			wantRooms: []*Room{
+				{
+					Chairs: map[rune]int{'P': 1},
+					Cells: []Position{
+						{2, 2}, {2, 3}, {2, 4}, {2, 5}, {2, 6}, {2, 7}, {2, 8},
+						{3, 2}, {3, 4}, {3, 6}, {3, 8},
+						{4, 2}, {4, 3}, {4, 4}, {4, 5}, {4, 6}, {4, 7}, {4, 8},
+					},
+					Placements: []Chair{{'P', Position{4, 5}}},
+				},
+			},
+		},
+		{
+			name: "columns count bytes, a multibyte title takes a cell per byte",
+			input: `+-----+
+|(ä)W|
++-----+`,
+			wantLines: 3,
+			wantRooms: []*Room{
+				{
+					Name:       "ä",
+					Chairs:     map[rune]int{'W': 1},
+					Cells:      []Position{{2, 2}, {2, 3}, {2, 4}, {2, 5}, {2, 6}},
+					Placements: []Chair{{'W', Position{2, 6}}},
+					Title:      Span{Position{2, 2}, 4},
+				},
+			},
+		},
+		{
+			name:      "strange character",
+			input:     "+---+\n| X |\n+---+\n",
+			wantErr:   true,
+			wantLines: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := ParsePlan(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePlan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(plan.Lines) != tt.wantLines {
+				t.Errorf("ParsePlan() got %d lines, want %d", len(plan.Lines), tt.wantLines)
+			}
+			if !reflect.DeepEqual(plan.Rooms, tt.wantRooms) {
+				t.Errorf("ParsePlan() rooms:\n%+v\nwant:\n%+v", plan.Rooms, tt.wantRooms)
+			}
+		})
+	}
+}
+
+func TestPlan_At(t *testing.T) {
+	plan := &Plan{Lines: []string{"+--+", "|W |"}}
+	for pos, want := range map[Position]rune{
+		{1, 1}: '+', {2, 2}: 'W', {2, 3}: ' ', {2, 5}: ' ', {3, 1}: ' ', {0, 0}: ' ',
+	} {
+		if got := plan.At(pos); got != want {
+			t.Errorf("At(%v) = %q, want %q", pos, got, want)
+		}
+	}
+}
+
+func TestPlan_RoomAt(t *testing.T) {
+	plan, err := ParsePlan(strings.NewReader(`+-----+-----+
+|(a) W|  (b)|
++-----+     |
+      |     |
+      +-----+
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		pos      Position
+		wantRoom string
+		wantKind CellKind
+	}{
+		{Position{2, 2}, "a", Floor},
+		{Position{2, 6}, "a", Floor},
+		{Position{2, 7}, "", Wall},
+		{Position{4, 8}, "b", Floor},
+		{Position{4, 3}, "", Outside},
+		{Position{1, 1}, "", Wall},
+		{Position{9, 9}, "", Outside},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pos.String(), func(t *testing.T) {
+			room, kind := plan.RoomAt(tt.pos)
+			if kind != tt.wantKind {
+				t.Errorf("RoomAt() kind = %s, want %s", kind, tt.wantKind)
+			}
+			if (room == nil) != (tt.wantKind != Floor) {
+				t.Fatalf("RoomAt() room = %v for a %s cell", room, kind)
+			}
+			if room != nil && room.Name != tt.wantRoom {
+				t.Errorf("RoomAt() room = %q, want %q", room.Name, tt.wantRoom)
+			}
+		})
+	}
+}
+
+// TestPlan_ConcurrentLookups shares a plan between goroutines, as the servers do: go test -race tells if that's safe.
+func TestPlan_ConcurrentLookups(t *testing.T) {
+	plan, err := ParsePlan(strings.NewReader("+-----+---+\n|(a) W|  C|\n+-----+---+"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if room, kind := plan.RoomAt(Position{Line: 2, Column: 2}); kind != Floor || room.Name != "a" {
+				t.Errorf("RoomAt() = %v, %v, want room a", room, kind)
+			}
+			plan.Problems()
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,555 @@
+// The contract for parsing floor plans, for the services that would rather not scrape JSON.
+// Regenerate the Go code with `make proto`.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.2
+// 	protoc        (unknown)
+// source: src/planpb/plan.proto
+
+package planpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Plan is a whole floor plan, as drawn.
+type Plan struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Text string `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+}
+
+func (x *Plan) Reset() {
+	*x = Plan{}
+	mi := &file_src_planpb_plan_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Plan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Plan) ProtoMessage() {}
+
+func (x *Plan) ProtoReflect() protoreflect.Message {
+	mi := &file_src_planpb_plan_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Plan.ProtoReflect.Descriptor instead.
+func (*Plan) Descriptor() ([]byte, []int) {
+	return file_src_planpb_plan_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Plan) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+// Line is a single line of a floor plan, without the line ending.
+type Line struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Text string `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+}
+
+func (x *Line) Reset() {
+	*x = Line{}
+	mi := &file_src_planpb_plan_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Line) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Line) ProtoMessage() {}
+
+func (x *Line) ProtoReflect() protoreflect.Message {
+	mi := &file_src_planpb_plan_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Line.ProtoReflect.Descriptor instead.
+func (*Line) Descriptor() ([]byte, []int) {
+	return file_src_planpb_plan_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Line) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+// Position points to a cell of the plan. Both the line and the column are 1-based.
+type Position struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Line   int32 `protobuf:"varint,1,opt,name=line,proto3" json:"line,omitempty"`
+	Column int32 `protobuf:"varint,2,opt,name=column,proto3" json:"column,omitempty"`
+}
+
+func (x *Position) Reset() {
+	*x = Position{}
+	mi := &file_src_planpb_plan_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Position) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Position) ProtoMessage() {}
+
+func (x *Position) ProtoReflect() protoreflect.Message {
+	mi := &file_src_planpb_plan_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Position.ProtoReflect.Descriptor instead.
+func (*Position) Descriptor() ([]byte, []int) {
+	return file_src_planpb_plan_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Position) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *Position) GetColumn() int32 {
+	if x != nil {
+		return x.Column
+	}
+	return 0
+}
+
+// Chair is a single chair and where it was drawn.
+type Chair struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// type is the chair type's letter: W, P, S or C.
+	Type     string    `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Position *Position `protobuf:"bytes,2,opt,name=position,proto3" json:"position,omitempty"`
+}
+
+func (x *Chair) Reset() {
+	*x = Chair{}
+	mi := &file_src_planpb_plan_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Chair) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Chair) ProtoMessage() {}
+
+func (x *Chair) ProtoReflect() protoreflect.Message {
+	mi := &file_src_planpb_plan_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Chair.ProtoReflect.Descriptor instead.
+func (*Chair) Descriptor() ([]byte, []int) {
+	return file_src_planpb_plan_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Chair) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Chair) GetPosition() *Position {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+// Room is a closed room.
+type Room struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// chairs counts the room's chairs by type letter, every type of the catalog included.
+	Chairs map[string]int32 `protobuf:"bytes,2,rep,name=chairs,proto3" json:"chairs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// area is how many floor cells the room has.
+	Area       int32    `protobuf:"varint,3,opt,name=area,proto3" json:"area,omitempty"`
+	Placements []*Chair `protobuf:"bytes,4,rep,name=placements,proto3" json:"placements,omitempty"`
+}
+
+func (x *Room) Reset() {
+	*x = Room{}
+	mi := &file_src_planpb_plan_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Room) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Room) ProtoMessage() {}
+
+func (x *Room) ProtoReflect() protoreflect.Message {
+	mi := &file_src_planpb_plan_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Room.ProtoReflect.Descriptor instead.
+func (*Room) Descriptor() ([]byte, []int) {
+	return file_src_planpb_plan_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Room) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Room) GetChairs() map[string]int32 {
+	if x != nil {
+		return x.Chairs
+	}
+	return nil
+}
+
+func (x *Room) GetArea() int32 {
+	if x != nil {
+		return x.Area
+	}
+	return 0
+}
+
+func (x *Room) GetPlacements() []*Chair {
+	if x != nil {
+		return x.Placements
+	}
+	return nil
+}
+
+// Diagnostic is a problem found in the plan.
+type Diagnostic struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// position is unset when the problem is not in a given place of the plan.
+	Position *Position `protobuf:"bytes,1,opt,name=position,proto3" json:"position,omitempty"`
+	Message  string    `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *Diagnostic) Reset() {
+	*x = Diagnostic{}
+	mi := &file_src_planpb_plan_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Diagnostic) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Diagnostic) ProtoMessage() {}
+
+func (x *Diagnostic) ProtoReflect() protoreflect.Message {
+	mi := &file_src_planpb_plan_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Diagnostic.ProtoReflect.Descriptor instead.
+func (*Diagnostic) Descriptor() ([]byte, []int) {
+	return file_src_planpb_plan_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Diagnostic) GetPosition() *Position {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *Diagnostic) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// ParseResult is what a plan comes down to.
+// A plan with problems still gets a result, with whatever the parser could make of it.
+type ParseResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// total counts the chairs of all the rooms by type letter, every type of the catalog included.
+	Total map[string]int32 `protobuf:"bytes,1,rep,name=total,proto3" json:"total,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// rooms are the closed rooms, sorted by name.
+	Rooms []*Room `protobuf:"bytes,2,rep,name=rooms,proto3" json:"rooms,omitempty"`
+	// diagnostics are the error that stopped the parser, or the rooms that never close.
+	Diagnostics []*Diagnostic `protobuf:"bytes,3,rep,name=diagnostics,proto3" json:"diagnostics,omitempty"`
+}
+
+func (x *ParseResult) Reset() {
+	*x = ParseResult{}
+	mi := &file_src_planpb_plan_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParseResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseResult) ProtoMessage() {}
+
+func (x *ParseResult) ProtoReflect() protoreflect.Message {
+	mi := &file_src_planpb_plan_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseResult.ProtoReflect.Descriptor instead.
+func (*ParseResult) Descriptor() ([]byte, []int) {
+	return file_src_planpb_plan_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ParseResult) GetTotal() map[string]int32 {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *ParseResult) GetRooms() []*Room {
+	if x != nil {
+		return x.Rooms
+	}
+	return nil
+}
+
+func (x *ParseResult) GetDiagnostics() []*Diagnostic {
+	if x != nil {
+		return x.Diagnostics
+	}
+	return nil
+}
+
+var File_src_planpb_plan_proto protoreflect.FileDescriptor
+
+var file_src_planpb_plan_proto_rawDesc = []byte{
+	0x0a, 0x15, 0x73, 0x72, 0x63, 0x2f, 0x70, 0x6c, 0x61, 0x6e, 0x70, 0x62, 0x2f, 0x70, 0x6c, 0x61,
+	0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x10, 0x65, 0x6e, 0x73, 0x70, 0x69, 0x72, 0x65,
+	0x64, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x22, 0x1a, 0x0a, 0x04, 0x50, 0x6c, 0x61,
+	0x6e, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x65, 0x78, 0x74, 0x22, 0x1a, 0x0a, 0x04, 0x4c, 0x69, 0x6e, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78,
+	0x74, 0x22, 0x36, 0x0a, 0x08, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a,
+	0x04, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x6c, 0x69, 0x6e,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x22, 0x53, 0x0a, 0x05, 0x43, 0x68, 0x61,
+	0x69, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x36, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x65, 0x6e, 0x73, 0x70, 0x69,
+	0x72, 0x65, 0x64, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xde,
+	0x01, 0x0a, 0x04, 0x52, 0x6f, 0x6f, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x3a, 0x0a, 0x06, 0x63,
+	0x68, 0x61, 0x69, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x65, 0x6e,
+	0x73, 0x70, 0x69, 0x72, 0x65, 0x64, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x6f, 0x6f, 0x6d, 0x2e, 0x43, 0x68, 0x61, 0x69, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x06, 0x63, 0x68, 0x61, 0x69, 0x72, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x65, 0x61, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x61, 0x72, 0x65, 0x61, 0x12, 0x37, 0x0a, 0x0a, 0x70,
+	0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x17, 0x2e, 0x65, 0x6e, 0x73, 0x70, 0x69, 0x72, 0x65, 0x64, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x2e,
+	0x76, 0x31, 0x2e, 0x43, 0x68, 0x61, 0x69, 0x72, 0x52, 0x0a, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d,
+	0x65, 0x6e, 0x74, 0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x69, 0x72, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
+	0x5e, 0x0a, 0x0a, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x12, 0x36, 0x0a,
+	0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x65, 0x6e, 0x73, 0x70, 0x69, 0x72, 0x65, 0x64, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x70, 0x6f, 0x73,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22,
+	0xf5, 0x01, 0x0a, 0x0b, 0x50, 0x61, 0x72, 0x73, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12,
+	0x3e, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28,
+	0x2e, 0x65, 0x6e, 0x73, 0x70, 0x69, 0x72, 0x65, 0x64, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x61, 0x72, 0x73, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x2e, 0x54, 0x6f,
+	0x74, 0x61, 0x6c, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12,
+	0x2c, 0x0a, 0x05, 0x72, 0x6f, 0x6f, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16,
+	0x2e, 0x65, 0x6e, 0x73, 0x70, 0x69, 0x72, 0x65, 0x64, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76,
+	0x31, 0x2e, 0x52, 0x6f, 0x6f, 0x6d, 0x52, 0x05, 0x72, 0x6f, 0x6f, 0x6d, 0x73, 0x12, 0x3e, 0x0a,
+	0x0b, 0x64, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x65, 0x6e, 0x73, 0x70, 0x69, 0x72, 0x65, 0x64, 0x2e, 0x70, 0x6c,
+	0x61, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63,
+	0x52, 0x0b, 0x64, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x73, 0x1a, 0x38, 0x0a,
+	0x0a, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x32, 0x93, 0x01, 0x0a, 0x0a, 0x50, 0x6c, 0x61, 0x6e,
+	0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x3e, 0x0a, 0x05, 0x50, 0x61, 0x72, 0x73, 0x65, 0x12,
+	0x16, 0x2e, 0x65, 0x6e, 0x73, 0x70, 0x69, 0x72, 0x65, 0x64, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x1a, 0x1d, 0x2e, 0x65, 0x6e, 0x73, 0x70, 0x69, 0x72,
+	0x65, 0x64, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x73, 0x65,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x45, 0x0a, 0x0a, 0x50, 0x61, 0x72, 0x73, 0x65, 0x4c,
+	0x69, 0x6e, 0x65, 0x73, 0x12, 0x16, 0x2e, 0x65, 0x6e, 0x73, 0x70, 0x69, 0x72, 0x65, 0x64, 0x2e,
+	0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x6e, 0x65, 0x1a, 0x1d, 0x2e, 0x65,
+	0x6e, 0x73, 0x70, 0x69, 0x72, 0x65, 0x64, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x76, 0x31, 0x2e,
+	0x50, 0x61, 0x72, 0x73, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x28, 0x01, 0x42, 0x2d, 0x0a,
+	0x14, 0x63, 0x6f, 0x6d, 0x2e, 0x65, 0x6e, 0x73, 0x70, 0x69, 0x72, 0x65, 0x64, 0x2e, 0x70, 0x6c,
+	0x61, 0x6e, 0x2e, 0x76, 0x31, 0x50, 0x01, 0x5a, 0x13, 0x65, 0x6e, 0x73, 0x70, 0x69, 0x72, 0x65,
+	0x64, 0x2f, 0x73, 0x72, 0x63, 0x2f, 0x70, 0x6c, 0x61, 0x6e, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_src_planpb_plan_proto_rawDescOnce sync.Once
+	file_src_planpb_plan_proto_rawDescData = file_src_planpb_plan_proto_rawDesc
+)
+
+func file_src_planpb_plan_proto_rawDescGZIP() []byte {
+	file_src_planpb_plan_proto_rawDescOnce.Do(func() {
+		file_src_planpb_plan_proto_rawDescData = protoimpl.X.CompressGZIP(file_src_planpb_plan_proto_rawDescData)
+	})
+	return file_src_planpb_plan_proto_rawDescData
+}
+
+var file_src_planpb_plan_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_src_planpb_plan_proto_goTypes = []any{
+	(*Plan)(nil),        // 0: enspired.plan.v1.Plan
+	(*Line)(nil),        // 1: enspired.plan.v1.Line
+	(*Position)(nil),    // 2: enspired.plan.v1.Position
+	(*Chair)(nil),       // 3: enspired.plan.v1.Chair
+	(*Room)(nil),        // 4: enspired.plan.v1.Room
+	(*Diagnostic)(nil),  // 5: enspired.plan.v1.Diagnostic
+	(*ParseResult)(nil), // 6: enspired.plan.v1.ParseResult
+	nil,                 // 7: enspired.plan.v1.Room.ChairsEntry
+	nil,                 // 8: enspired.plan.v1.ParseResult.TotalEntry
+}
+var file_src_planpb_plan_proto_depIdxs = []int32{
+	2, // 0: enspired.plan.v1.Chair.position:type_name -> enspired.plan.v1.Position
+	7, // 1: enspired.plan.v1.Room.chairs:type_name -> enspired.plan.v1.Room.ChairsEntry
+	3, // 2: enspired.plan.v1.Room.placements:type_name -> enspired.plan.v1.Chair
+	2, // 3: enspired.plan.v1.Diagnostic.position:type_name -> enspired.plan.v1.Position
+	8, // 4: enspired.plan.v1.ParseResult.total:type_name -> enspired.plan.v1.ParseResult.TotalEntry
+	4, // 5: enspired.plan.v1.ParseResult.rooms:type_name -> enspired.plan.v1.Room
+	5, // 6: enspired.plan.v1.ParseResult.diagnostics:type_name -> enspired.plan.v1.Diagnostic
+	0, // 7: enspired.plan.v1.PlanParser.Parse:input_type -> enspired.plan.v1.Plan
+	1, // 8: enspired.plan.v1.PlanParser.ParseLines:input_type -> enspired.plan.v1.Line
+	6, // 9: enspired.plan.v1.PlanParser.Parse:output_type -> enspired.plan.v1.ParseResult
+	6, // 10: enspired.plan.v1.PlanParser.ParseLines:output_type -> enspired.plan.v1.ParseResult
+	9, // [9:11] is the sub-list for method output_type
+	7, // [7:9] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_src_planpb_plan_proto_init() }
+func file_src_planpb_plan_proto_init() {
+	if File_src_planpb_plan_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_src_planpb_plan_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_src_planpb_plan_proto_goTypes,
+		DependencyIndexes: file_src_planpb_plan_proto_depIdxs,
+		MessageInfos:      file_src_planpb_plan_proto_msgTypes,
+	}.Build()
+	File_src_planpb_plan_proto = out.File
+	file_src_planpb_plan_proto_rawDesc = nil
+	file_src_planpb_plan_proto_goTypes = nil
+	file_src_planpb_plan_proto_depIdxs = nil
+}
@@ -0,0 +1,85 @@
+package planpb
+
+import (
+	"context"
+	"enspired/src"
+	"errors"
+	"io"
+	"strings"
+
+	"google.golang.org/grpc"
+)
+
+// Service is the PlanParser gRPC service, giving every call a FlatParser of its own.
+type Service struct {
+	UnimplementedPlanParserServer
+}
+
+// Parse parses a whole plan at once.
+// A plan the parser gives up on still gets a result, with what was found up to the error and the error as a diagnostic.
+func (s *Service) Parse(_ context.Context, plan *Plan) (*ParseResult, error) {
+	parser := src.NewRoomParser()
+	parsed, err := parser.ReadPlan(strings.NewReader(plan.GetText()))
+	if err != nil {
+		return newParseResult(parser.Plan(), err), nil
+	}
+	return newParseResult(parsed, nil), nil
+}
+
+// ParseLines ingests the lines as they arrive, and answers once the client is done sending them
+// or as soon as a line can't be parsed.
+func (s *Service) ParseLines(stream grpc.ClientStreamingServer[Line, ParseResult]) error {
+	parser := src.NewRoomParser()
+	for {
+		line, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(newParseResult(parser.Plan(), nil))
+		}
+		if err != nil {
+			return err
+		}
+		if err := parser.Ingest(line.GetText()); err != nil {
+			return stream.SendAndClose(newParseResult(parser.Plan(), err))
+		}
+	}
+}
+
+// newParseResult converts a plan to its protobuf form.
+// The diagnostics are parseErr if there's one, or else the plan's problems.
+func newParseResult(plan *src.Plan, parseErr error) *ParseResult {
+	result := plan.Result()
+	message := &ParseResult{Total: counts(result.Total)}
+	for _, room := range result.Rooms {
+		roomMessage := &Room{Name: room.Name, Chairs: counts(room.Chairs), Area: int32(room.Area)}
+		for _, chair := range room.Placements {
+			roomMessage.Placements = append(roomMessage.Placements,
+				&Chair{Type: chair.Type, Position: position(chair.Position)})
+		}
+		message.Rooms = append(message.Rooms, roomMessage)
+	}
+
+	problems := plan.Problems()
+	if parseErr != nil {
+		problems = []*src.ParseError{src.AsParseError(parseErr)}
+	}
+	for _, problem := range problems {
+		diagnostic := &Diagnostic{Message: problem.Message}
+		if problem.Position != (src.Position{}) {
+			diagnostic.Position = position(problem.Position)
+		}
+		message.Diagnostics = append(message.Diagnostics, diagnostic)
+	}
+	return message
+}
+
+func position(pos src.Position) *Position {
+	return &Position{Line: int32(pos.Line), Column: int32(pos.Column)}
+}
+
+func counts(chairs map[string]int) map[string]int32 {
+	converted := make(map[string]int32, len(chairs))
+	for chairType, count := range chairs {
+		converted[chairType] = int32(count)
+	}
+	return converted
+}
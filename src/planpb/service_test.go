@@ -0,0 +1,95 @@
+package planpb
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestClient(t *testing.T) PlanParserClient {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	RegisterPlanParserServer(server, &Service{})
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return NewPlanParserClient(conn)
+}
+
+func TestService(t *testing.T) {
+	client := newTestClient(t)
+
+	tests := []struct {
+		name string
+		plan string
+		want string
+	}{
+		{
+			name: "rooms",
+			plan: "+------+---+\n|(a) W |  C|\n+------+---+\n",
+			want: `{"total":{"C":1,"P":0,"S":0,"W":1},"rooms":[
+				{"chairs":{"C":1,"P":0,"S":0,"W":0},"area":3,"placements":[{"type":"C","position":{"line":2,"column":11}}]},
+				{"name":"a","chairs":{"C":0,"P":0,"S":0,"W":1},"area":6,"placements":[{"type":"W","position":{"line":2,"column":6}}]}]}`,
+		},
+		{
+			name: "parse error",
+			plan: "+------+---+\n|(a) W |  C|\n|   X  |   |\n+------+---+\n",
+			want: `{"total":{"C":0,"P":0,"S":0,"W":0},
+				"diagnostics":[{"position":{"line":3,"column":5},"message":"strange character encountered: X"}]}`,
+		},
+		{
+			name: "unclosed room",
+			plan: "+------+\n|(a) W |\n",
+			want: `{"total":{"C":0,"P":0,"S":0,"W":0},
+				"diagnostics":[{"position":{"line":2,"column":2},"message":"room a never closes"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := &ParseResult{}
+			if err := protojson.Unmarshal([]byte(tt.want), want); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := client.Parse(context.Background(), &Plan{Text: tt.plan})
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !proto.Equal(got, want) {
+				t.Errorf("Parse() = %v\nwant %v", got, want)
+			}
+
+			stream, err := client.ParseLines(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, line := range strings.Split(strings.TrimSuffix(tt.plan, "\n"), "\n") {
+				if err := stream.Send(&Line{Text: line}); err != nil {
+					break // the server answered already
+				}
+			}
+			got, err = stream.CloseAndRecv()
+			if err != nil {
+				t.Fatalf("ParseLines() error = %v", err)
+			}
+			if !proto.Equal(got, want) {
+				t.Errorf("ParseLines() = %v\nwant %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package src
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChairOrderRule says how much of a chair type to produce on top of what the plans hold.
+type ChairOrderRule struct {
+	// SparePercent is added to what the plans hold, rounded up to whole chairs.
+	// It counts to the hundredth of a percent.
+	SparePercent float64 `json:"spare_percent"`
+	// BatchSize is how many chairs of the type get produced at once: the order is rounded up to whole batches.
+	// Zero means one chair at a time.
+	BatchSize int `json:"batch_size"`
+}
+
+// OrderConfig holds the order rules of each chair type, by the type's letter.
+// Chair types without a rule get no spares and are produced one at a time.
+type OrderConfig struct {
+	Chairs map[string]ChairOrderRule `json:"chairs"`
+}
+
+// ReadOrderConfig decodes an OrderConfig from JSON:
+//
+//	{"chairs": {"W": {"spare_percent": 10, "batch_size": 12}}}
+func ReadOrderConfig(reader io.Reader) (*OrderConfig, error) {
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	config := &OrderConfig{}
+	if err := decoder.Decode(config); err != nil {
+		return nil, fmt.Errorf("can't decode the order config: %w", err)
+	}
+	for code, rule := range config.Chairs {
+		if len([]rune(code)) != 1 || !IsChair([]rune(code)[0]) {
+			return nil, fmt.Errorf("order config: %q is not a chair type", code)
+		}
+		if rule.SparePercent < 0 || rule.BatchSize < 0 {
+			return nil, fmt.Errorf("order config: negative spare percent or batch size for %s", code)
+		}
+	}
+	return config, nil
+}
+
+// ApartmentChairs is what an apartment's plan holds.
+// Without an Apartment, it's what the rooms of the building that belong to no apartment hold, like stairwells.
+type ApartmentChairs struct {
+	Building  string
+	Apartment string
+	Chairs    map[rune]int
+}
+
+// OrderLine is how many chairs of a type to produce.
+type OrderLine struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	// Needed is what the plans hold.
+	Needed int `json:"needed"`
+	// Spare is what gets produced on top, for breakage.
+	Spare     int `json:"spare"`
+	BatchSize int `json:"batch_size"`
+	Batches   int `json:"batches"`
+	// Quantity is what gets produced: needed plus spare, rounded up to whole batches.
+	Quantity int `json:"quantity"`
+}
+
+// ApartmentOrder is the breakdown of the chairs an apartment needs.
+type ApartmentOrder struct {
+	Name   string         `json:"name"`
+	Chairs map[string]int `json:"chairs"`
+}
+
+// BuildingOrder is the breakdown of the chairs a building needs, apartment by apartment.
+type BuildingOrder struct {
+	Name       string           `json:"name"`
+	Chairs     map[string]int   `json:"chairs"`
+	Apartments []ApartmentOrder `json:"apartments"`
+	// CommonAreas are the chairs of the rooms that belong to no apartment, if there are any.
+	CommonAreas map[string]int `json:"common_areas,omitempty"`
+}
+
+// ProductionOrder says how many chairs to produce for a set of apartments, and who needs them.
+type ProductionOrder struct {
+	Lines     []OrderLine     `json:"lines"`
+	Buildings []BuildingOrder `json:"buildings"`
+}
+
+// NewProductionOrder adds up what the apartments need, then applies the config's spares and batch sizes.
+// Buildings and apartments are ordered by name, the order lines follow the chair catalog.
+func NewProductionOrder(apartments []ApartmentChairs, config *OrderConfig) *ProductionOrder {
+	order := &ProductionOrder{Lines: []OrderLine{}, Buildings: []BuildingOrder{}}
+
+	needed := map[rune]int{}
+	buildings := map[string]*BuildingOrder{}
+	for _, apartment := range apartments {
+		building, found := buildings[apartment.Building]
+		if !found {
+			building = &BuildingOrder{Name: apartment.Building, Chairs: catalogCounts(nil)}
+			buildings[apartment.Building] = building
+		}
+		if apartment.Apartment == "" {
+			if building.CommonAreas == nil {
+				building.CommonAreas = catalogCounts(nil)
+			}
+			for _, chairType := range ChairTypes {
+				building.CommonAreas[string(chairType.Code)] += apartment.Chairs[chairType.Code]
+			}
+		} else {
+			building.Apartments = append(building.Apartments,
+				ApartmentOrder{Name: apartment.Apartment, Chairs: catalogCounts(apartment.Chairs)})
+		}
+		for _, chairType := range ChairTypes {
+			building.Chairs[string(chairType.Code)] += apartment.Chairs[chairType.Code]
+			needed[chairType.Code] += apartment.Chairs[chairType.Code]
+		}
+	}
+	for _, building := range buildings {
+		sort.SliceStable(building.Apartments, func(i, j int) bool {
+			return building.Apartments[i].Name < building.Apartments[j].Name
+		})
+		order.Buildings = append(order.Buildings, *building)
+	}
+	sort.Slice(order.Buildings, func(i, j int) bool { return order.Buildings[i].Name < order.Buildings[j].Name })
+
+	for _, chairType := range ChairTypes {
+		var rule ChairOrderRule
+		if config != nil {
+			rule = config.Chairs[string(chairType.Code)]
+		}
+		line := OrderLine{
+			Type:      string(chairType.Code),
+			Name:      chairType.Name,
+			Needed:    needed[chairType.Code],
+			Spare:     spareChairs(needed[chairType.Code], rule.SparePercent),
+			BatchSize: max(rule.BatchSize, 1),
+		}
+		line.Batches = (line.Needed + line.Spare + line.BatchSize - 1) / line.BatchSize
+		line.Quantity = line.Batches * line.BatchSize
+		order.Lines = append(order.Lines, line)
+	}
+	return order
+}
+
+// spareChairs is percent of needed, rounded up to whole chairs.
+// The percent is taken in basis points, hundredths of a percent, so the sum stays in integers:
+// 64.4% of 250 is 161, where float64 maths makes it 161.00000000000003 and rounds it up to 162.
+func spareChairs(needed int, percent float64) int {
+	basisPoints := int(math.Round(percent * 100))
+	return (needed*basisPoints + 9999) / 10000
+}
+
+// String is the order as text: what to produce first, then who needs it.
+//
+// W (wooden chair): needed 14, spare 2, produce 24 in 2 batches of 12
+// ...
+// building a:
+// W: 14, P: 7, S: 3, C: 1
+// apartment 1:
+// W: 14, P: 7, S: 3, C: 1
+// common areas:
+// W: 0, P: 0, S: 0, C: 1
+func (o *ProductionOrder) String() string {
+	var lines []string
+	for _, line := range o.Lines {
+		lines = append(lines, fmt.Sprintf("%s (%s): needed %d, spare %d, produce %d in %d batches of %d",
+			line.Type, line.Name, line.Needed, line.Spare, line.Quantity, line.Batches, line.BatchSize))
+	}
+	for _, building := range o.Buildings {
+		lines = append(lines, fmt.Sprintf("building %s:", building.Name), formatCounts(building.Chairs))
+		for _, apartment := range building.Apartments {
+			lines = append(lines, fmt.Sprintf("apartment %s:", apartment.Name), formatCounts(apartment.Chairs))
+		}
+		if building.CommonAreas != nil {
+			lines = append(lines, "common areas:", formatCounts(building.CommonAreas))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// WriteCSV writes the order as a single CSV table, with a scope column telling the kinds of rows apart:
+// "order" rows say what to produce, "building", "apartment" and "common_areas" rows who needs it.
+func (o *ProductionOrder) WriteCSV(writer io.Writer) error {
+	w := csv.NewWriter(writer)
+	_ = w.Write([]string{"scope", "building", "apartment", "type", "needed", "spare", "batch_size", "batches", "quantity"})
+	for _, line := range o.Lines {
+		_ = w.Write([]string{"order", "", "", line.Type, strconv.Itoa(line.Needed), strconv.Itoa(line.Spare),
+			strconv.Itoa(line.BatchSize), strconv.Itoa(line.Batches), strconv.Itoa(line.Quantity)})
+	}
+	for _, building := range o.Buildings {
+		for _, chairType := range ChairTypes {
+			code := string(chairType.Code)
+			_ = w.Write([]string{"building", building.Name, "", code, strconv.Itoa(building.Chairs[code]), "", "", "", ""})
+		}
+		for _, apartment := range building.Apartments {
+			for _, chairType := range ChairTypes {
+				code := string(chairType.Code)
+				_ = w.Write([]string{"apartment", building.Name, apartment.Name, code,
+					strconv.Itoa(apartment.Chairs[code]), "", "", "", ""})
+			}
+		}
+		if building.CommonAreas != nil {
+			for _, chairType := range ChairTypes {
+				code := string(chairType.Code)
+				_ = w.Write([]string{"common_areas", building.Name, "", code,
+					strconv.Itoa(building.CommonAreas[code]), "", "", "", ""})
+			}
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// catalogCounts keys chair counts by type letter, with every type of the catalog present.
+func catalogCounts(chairs map[rune]int) map[string]int {
+	counts := make(map[string]int, len(ChairTypes))
+	for _, chairType := range ChairTypes {
+		counts[string(chairType.Code)] = chairs[chairType.Code]
+	}
+	return counts
+}
+
+// formatCounts is ChairCounts for counts keyed by type letter.
+func formatCounts(counts map[string]int) string {
+	chairs := make(map[rune]int, len(counts))
+	for code, count := range counts {
+		chairs[[]rune(code)[0]] = count
+	}
+	return ChairCounts(chairs)
+}
@@ -0,0 +1,131 @@
+package src
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestNewProductionOrder(t *testing.T) {
+	config, err := ReadOrderConfig(strings.NewReader(
+		`{"chairs": {"W": {"spare_percent": 10, "batch_size": 12}, "P": {"spare_percent": 50}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	order := NewProductionOrder([]ApartmentChairs{
+		{Building: "b", Apartment: "2", Chairs: map[rune]int{'W': 10, 'P': 1}},
+		{Building: "a", Apartment: "1", Chairs: map[rune]int{'W': 11, 'C': 1}},
+		{Building: "b", Apartment: "1", Chairs: map[rune]int{'S': 2}},
+	}, config)
+
+	wantText := `W (wooden chair): needed 21, spare 3, produce 24 in 2 batches of 12
+P (plastic chair): needed 1, spare 1, produce 2 in 2 batches of 1
+S (sofa chair): needed 2, spare 0, produce 2 in 2 batches of 1
+C (china chair): needed 1, spare 0, produce 1 in 1 batches of 1
+building a:
+W: 11, P: 0, S: 0, C: 1
+apartment 1:
+W: 11, P: 0, S: 0, C: 1
+building b:
+W: 10, P: 1, S: 2, C: 0
+apartment 1:
+W: 0, P: 0, S: 2, C: 0
+apartment 2:
+W: 10, P: 1, S: 0, C: 0`
+	if got := order.String(); got != wantText {
+		t.Errorf("String() =\n%s\nwant:\n%s", got, wantText)
+	}
+
+	var csv strings.Builder
+	if err := order.WriteCSV(&csv); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csv.String()), "\n")
+	// header, 4 order lines, 3 buildings and apartments * 4 chair types
+	if len(lines) != 1+4+(2+3)*4 {
+		t.Fatalf("WriteCSV() wrote %d lines:\n%s", len(lines), csv.String())
+	}
+	for i, want := range map[int]string{
+		1:  "order,,,W,21,3,12,2,24",
+		5:  "building,a,,W,11,,,,",
+		9:  "apartment,a,1,W,11,,,,",
+		24: "apartment,b,2,C,0,,,,",
+	} {
+		if lines[i] != want {
+			t.Errorf("WriteCSV() line %d = %s, want %s", i, lines[i], want)
+		}
+	}
+}
+
+func TestNewProductionOrder_CommonAreas(t *testing.T) {
+	order := NewProductionOrder([]ApartmentChairs{
+		{Building: "a", Apartment: "1", Chairs: map[rune]int{'W': 2}},
+		{Building: "a", Chairs: map[rune]int{'C': 1}},
+		{Building: "a", Chairs: map[rune]int{'C': 2, 'P': 1}},
+	}, nil)
+
+	wantText := `building a:
+W: 2, P: 1, S: 0, C: 3
+apartment 1:
+W: 2, P: 0, S: 0, C: 0
+common areas:
+W: 0, P: 1, S: 0, C: 3`
+	if got := order.String(); !strings.HasSuffix(got, "\n"+wantText) {
+		t.Errorf("String() =\n%s\nwant it to end with:\n%s", got, wantText)
+	}
+
+	var csv strings.Builder
+	if err := order.WriteCSV(&csv); err != nil {
+		t.Fatal(err)
+	}
+	if want := "common_areas,a,,C,3,,,,\n"; !strings.HasSuffix(csv.String(), want) {
+		t.Errorf("WriteCSV() =\n%s\nwant it to end with %q", csv.String(), want)
+	}
+}
+
+func TestNewProductionOrder_Spares(t *testing.T) {
+	tests := []struct {
+		needed  int
+		percent float64
+		want    int
+	}{
+		{needed: 21, percent: 10, want: 3},
+		{needed: 10, percent: 10, want: 1},
+		{needed: 0, percent: 50, want: 0},
+		{needed: 250, percent: 64.4, want: 161},
+		{needed: 375, percent: 8.8, want: 33},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v%% of %d", tt.percent, tt.needed), func(t *testing.T) {
+			order := NewProductionOrder(
+				[]ApartmentChairs{{Building: "a", Apartment: "1", Chairs: map[rune]int{'W': tt.needed}}},
+				&OrderConfig{Chairs: map[string]ChairOrderRule{"W": {SparePercent: tt.percent}}},
+			)
+			if got := order.Lines[0].Spare; got != tt.want {
+				t.Errorf("Spare = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadOrderConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "empty", input: `{}`},
+		{name: "valid", input: `{"chairs": {"S": {"batch_size": 4}}}`},
+		{name: "not a chair", input: `{"chairs": {"X": {"batch_size": 4}}}`, wantErr: true},
+		{name: "negative spares", input: `{"chairs": {"W": {"spare_percent": -1}}}`, wantErr: true},
+		{name: "unknown field", input: `{"chairs": {"W": {"spares": 1}}}`, wantErr: true},
+		{name: "not JSON", input: `W: 10%`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadOrderConfig(strings.NewReader(tt.input)); (err != nil) != tt.wantErr {
+				t.Errorf("ReadOrderConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
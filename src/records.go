@@ -0,0 +1,154 @@
+package src
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// RecordLayout describes the fixed-width records the old system's batch importer reads:
+// one line per room, the room's name in a field of NameWidth bytes, padded with spaces or truncated,
+// then a field of CountWidth digits per chair type, zero-padded, in catalog order.
+type RecordLayout struct {
+	NameWidth  int `json:"name_width"`
+	CountWidth int `json:"count_width"`
+	// Total is the name of a record holding the chair totals, written before the rooms. None is written if empty.
+	Total string `json:"total,omitempty"`
+}
+
+// DefaultRecordLayout is the layout the importer was set up with.
+var DefaultRecordLayout = RecordLayout{NameWidth: 20, CountWidth: 4}
+
+// ReadRecordLayout decodes a RecordLayout from JSON:
+//
+//	{"name_width": 20, "count_width": 4, "total": "TOTAL"}
+func ReadRecordLayout(reader io.Reader) (*RecordLayout, error) {
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	layout := &RecordLayout{}
+	if err := decoder.Decode(layout); err != nil {
+		return nil, fmt.Errorf("can't decode the record layout: %w", err)
+	}
+	if err := layout.validate(); err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+func (l *RecordLayout) validate() error {
+	if l.NameWidth <= 0 || l.CountWidth <= 0 {
+		return errors.New("record layout: the name and count widths must be positive")
+	}
+	// wider than that, the counts wouldn't fit an int anyway
+	if l.CountWidth > 18 {
+		return fmt.Errorf("record layout: count width %d is more than 18 digits", l.CountWidth)
+	}
+	if len(l.Total) > l.NameWidth {
+		return fmt.Errorf("record layout: the total record's name %q doesn't fit in %d bytes", l.Total, l.NameWidth)
+	}
+	return nil
+}
+
+// width is the length of a record, in bytes, line break excluded.
+func (l *RecordLayout) width() int {
+	return l.NameWidth + len(ChairTypes)*l.CountWidth
+}
+
+// WriteRecords writes the result as fixed-width records, the total first if the layout has one, then the rooms.
+// A count too big for its field is an error, as the importer would read it wrong:
+// names are truncated, counts can't be.
+func WriteRecords(writer io.Writer, result *Result, layout *RecordLayout) error {
+	if err := layout.validate(); err != nil {
+		return err
+	}
+	w := bufio.NewWriter(writer)
+	if layout.Total != "" {
+		if err := writeRecord(w, layout.Total, result.Total, layout); err != nil {
+			return err
+		}
+	}
+	for _, room := range result.Rooms {
+		if err := writeRecord(w, room.Name, room.Chairs, layout); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+func writeRecord(w *bufio.Writer, name string, chairs map[string]int, layout *RecordLayout) error {
+	name = truncate(name, layout.NameWidth)
+	w.WriteString(name)
+	w.WriteString(strings.Repeat(" ", layout.NameWidth-len(name)))
+	for _, chairType := range ChairTypes {
+		count := chairs[string(chairType.Code)]
+		field := fmt.Sprintf("%0*d", layout.CountWidth, count)
+		if count < 0 || len(field) > layout.CountWidth {
+			return fmt.Errorf("%d %s in %q don't fit in %d digits", count, chairType.Name, name, layout.CountWidth)
+		}
+		w.WriteString(field)
+	}
+	return w.WriteByte('\n')
+}
+
+// truncate cuts s down to at most width bytes, without splitting a character.
+func truncate(s string, width int) string {
+	if len(s) <= width {
+		return s
+	}
+	for width > 0 && !utf8.RuneStart(s[width]) {
+		width--
+	}
+	return s[:width]
+}
+
+// ReadRecords reads fixed-width records back into a result, the way the importer does:
+// every line must be exactly as long as the layout says, and every count field all digits.
+// The names lose their padding. If the layout has a total record, the first record must be it.
+func ReadRecords(reader io.Reader, layout *RecordLayout) (*Result, error) {
+	if err := layout.validate(); err != nil {
+		return nil, err
+	}
+	result := &Result{Total: catalogCounts(nil), Rooms: []RoomResult{}}
+	scanner := bufio.NewScanner(reader)
+	for line := 1; scanner.Scan(); line++ {
+		record := scanner.Text()
+		if len(record) != layout.width() {
+			return nil, fmt.Errorf("[record %d] %d bytes long, the layout says %d", line, len(record), layout.width())
+		}
+		name := strings.TrimRight(record[:layout.NameWidth], " ")
+		chairs := make(map[string]int, len(ChairTypes))
+		for i, chairType := range ChairTypes {
+			start := layout.NameWidth + i*layout.CountWidth
+			field := record[start : start+layout.CountWidth]
+			if strings.Trim(field, "0123456789") != "" {
+				return nil, fmt.Errorf("[record %d] %s count %q is not a number", line, chairType.Name, field)
+			}
+			chairs[string(chairType.Code)], _ = strconv.Atoi(field)
+		}
+
+		if layout.Total == "" || line > 1 {
+			result.Rooms = append(result.Rooms, RoomResult{Name: name, Chairs: chairs})
+			continue
+		}
+		if name != layout.Total {
+			return nil, fmt.Errorf("[record 1] %q, the layout wants the total record %q first", name, layout.Total)
+		}
+		result.Total = chairs
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("can't read the records: %w", err)
+	}
+	if layout.Total == "" {
+		for _, room := range result.Rooms {
+			for code, count := range room.Chairs {
+				result.Total[code] += count
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,131 @@
+package src
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteRecords(t *testing.T) {
+	plan, err := NewRoomParser().ReadPlan(strings.NewReader(`+-----------------------+-------+
+|(a very long room name)|(b) P  |
+|   W   W               +---+ P |
++-----------------------+   |   |
+                        +---+---+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	layout := &RecordLayout{NameWidth: 12, CountWidth: 3, Total: "TOTAL"}
+
+	var records strings.Builder
+	if err := WriteRecords(&records, plan.Result(), layout); err != nil {
+		t.Fatal(err)
+	}
+	want := `TOTAL       002002000000
+            000000000000
+a very long 002000000000
+b           000002000000
+`
+	if records.String() != want {
+		t.Errorf("WriteRecords() =\n%s\nwant:\n%s", records.String(), want)
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		got, err := ReadRecords(strings.NewReader(records.String()), layout)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the records only keep the names, cut down to the field and without the padding, and the chairs
+		want := plan.Result()
+		for i, room := range want.Rooms {
+			want.Rooms[i] = RoomResult{Name: strings.TrimRight(truncate(room.Name, layout.NameWidth), " "), Chairs: room.Chairs}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ReadRecords() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("round trip without total", func(t *testing.T) {
+		layout := &DefaultRecordLayout
+		var records strings.Builder
+		if err := WriteRecords(&records, plan.Result(), layout); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ReadRecords(strings.NewReader(records.String()), layout)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var again strings.Builder
+		if err := WriteRecords(&again, got, layout); err != nil {
+			t.Fatal(err)
+		}
+		if again.String() != records.String() {
+			t.Errorf("records written again =\n%s\nwant:\n%s", again.String(), records.String())
+		}
+		if !reflect.DeepEqual(got.Total, plan.Result().Total) {
+			t.Errorf("ReadRecords() total = %v, want %v", got.Total, plan.Result().Total)
+		}
+	})
+
+	t.Run("count too big", func(t *testing.T) {
+		result := &Result{Rooms: []RoomResult{{Name: "hall", Chairs: map[string]int{"W": 1000}}}}
+		if err := WriteRecords(&strings.Builder{}, result, layout); err == nil {
+			t.Error("WriteRecords() = nil, want an error for 1000 chairs in 3 digits")
+		}
+	})
+}
+
+func TestTruncate(t *testing.T) {
+	for _, tt := range []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"kitchen", 10, "kitchen"},
+		{"kitchen", 4, "kitc"},
+		// ü is 2 bytes, it doesn't get cut in half
+		{"küche", 2, "k"},
+		{"küche", 3, "kü"},
+	} {
+		if got := truncate(tt.s, tt.width); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestReadRecords_Errors(t *testing.T) {
+	layout := &RecordLayout{NameWidth: 4, CountWidth: 2, Total: "T"}
+	for _, tt := range []struct {
+		name    string
+		layout  *RecordLayout
+		records string
+		want    string
+	}{
+		{"short record", layout, "T   01020304\nhall0102030\n", "[record 2] 11 bytes long, the layout says 12"},
+		{"not a number", layout, "T   01020304\nhall01 20304\n", `[record 2] plastic chair count " 2" is not a number`},
+		{"no total", layout, "hall01020304\n", `[record 1] "hall", the layout wants the total record "T" first`},
+		{"bad layout", &RecordLayout{}, "", "record layout: the name and count widths must be positive"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadRecords(strings.NewReader(tt.records), tt.layout)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("ReadRecords() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadRecordLayout(t *testing.T) {
+	layout, err := ReadRecordLayout(strings.NewReader(`{"name_width": 30, "count_width": 5, "total": "TOTAL"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (RecordLayout{NameWidth: 30, CountWidth: 5, Total: "TOTAL"}); *layout != want {
+		t.Errorf("ReadRecordLayout() = %+v, want %+v", *layout, want)
+	}
+	for _, config := range []string{`{"name_width": 30}`, `{"name_width": 3, "count_width": 2, "total": "TOTAL"}`, `{"width": 3}`} {
+		if _, err := ReadRecordLayout(strings.NewReader(config)); err == nil {
+			t.Errorf("ReadRecordLayout(%s) = nil error, want one", config)
+		}
+	}
+}
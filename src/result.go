@@ -0,0 +1,85 @@
+package src
+
+// Result is what a plan comes down to, in a shape that's easy to serialize:
+// the chair totals, then the rooms sorted by name.
+// Chair counts are keyed by type letter and list every type of the catalog.
+type Result struct {
+	Total map[string]int `json:"total"`
+	// Floors are the totals of each floor, for building plans with floor headers.
+	Floors []FloorResult `json:"floors,omitempty"`
+	// Apartments are the totals of each apartment, for plans with apartment labels.
+	Apartments []ApartmentResult `json:"apartments,omitempty"`
+	// Categories are the totals of each room category, for categorized plans.
+	Categories []CategoryResult `json:"categories,omitempty"`
+	Rooms      []RoomResult     `json:"rooms"`
+}
+
+// CategoryResult is a room category of a Result.
+type CategoryResult struct {
+	Name  string         `json:"name"`
+	Total map[string]int `json:"total"`
+	// Rooms are the names of the category's rooms.
+	Rooms []string `json:"rooms"`
+}
+
+// ApartmentResult is an apartment of a Result.
+type ApartmentResult struct {
+	Name  string         `json:"name"`
+	Total map[string]int `json:"total"`
+	// Rooms are the names of the apartment's rooms.
+	Rooms []string `json:"rooms"`
+}
+
+// FloorResult is a floor of a Result.
+type FloorResult struct {
+	Name  string         `json:"name"`
+	Total map[string]int `json:"total"`
+}
+
+// RoomResult is a room of a Result.
+type RoomResult struct {
+	Name     string         `json:"name"`
+	Category string         `json:"category,omitempty"`
+	Chairs   map[string]int `json:"chairs"`
+	// Area is how many floor cells the room has.
+	Area int `json:"area,omitempty"`
+	// Placements are the room's chairs, one by one.
+	Placements []ChairPlacement `json:"placements,omitempty"`
+}
+
+// ChairPlacement is where a chair is in the plan.
+type ChairPlacement struct {
+	Type string `json:"type"`
+	Position
+}
+
+// Result sums up the plan.
+func (p *Plan) Result() *Result {
+	result := &Result{Total: catalogCounts(p.Totals()), Rooms: []RoomResult{}}
+	for _, floor := range p.Floors {
+		result.Floors = append(result.Floors, FloorResult{Name: floor.Name, Total: catalogCounts(p.FloorTotals(floor.Name))})
+	}
+	for _, apartment := range p.Apartments() {
+		apartmentResult := ApartmentResult{Name: apartment.Name, Total: catalogCounts(apartment.Totals())}
+		for _, room := range apartment.Rooms {
+			apartmentResult.Rooms = append(apartmentResult.Rooms, room.Name)
+		}
+		result.Apartments = append(result.Apartments, apartmentResult)
+	}
+	for _, category := range p.Categories() {
+		categoryResult := CategoryResult{Name: category.Name, Total: catalogCounts(category.Totals())}
+		for _, room := range category.Rooms {
+			categoryResult.Rooms = append(categoryResult.Rooms, room.Name)
+		}
+		result.Categories = append(result.Categories, categoryResult)
+	}
+	for _, room := range p.Rooms {
+		roomResult := RoomResult{Name: room.Name, Category: room.Category, Chairs: catalogCounts(room.Chairs), Area: len(room.Cells)}
+		for _, chair := range room.Placements {
+			roomResult.Placements = append(roomResult.Placements,
+				ChairPlacement{Type: string(chair.Type), Position: chair.Position})
+		}
+		result.Rooms = append(result.Rooms, roomResult)
+	}
+	return result
+}
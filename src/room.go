@@ -0,0 +1,93 @@
+package src
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Position points to a cell of the plan.
+// Both the line and the column are 1-based, the way an editor shows them.
+// The column counts bytes of the line, not runes.
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("line %d, column %d", p.Line, p.Column)
+}
+
+// Span is a run of cells on a single line, starting at Position.
+type Span struct {
+	Position
+	Length int
+}
+
+// Chair is a single chair, as drawn in the plan.
+type Chair struct {
+	Type rune
+	Position
+}
+
+// Room is a closed room, as the parser found it.
+type Room struct {
+	Name   string
+	Chairs map[rune]int
+	// Cells are the room's floor cells, in the order the parser met them: line by line, left to right.
+	Cells []Position
+	// Placements are the room's chairs, one by one.
+	Placements []Chair
+	// Title is where the room's name is written, parentheses included.
+	// It's the zero Span if the room has no name.
+	Title Span
+	// Floor is the name of the floor the room is on, if the plan has floors. The room's Name starts with it.
+	Floor string
+	// Apartment is the apartment named by the label in the room, like [apt 3B], if it has one.
+	// The other rooms of the apartment are found through its doors, see Plan.Apartments.
+	Apartment string
+	// Label is where the apartment label is, brackets included.
+	Label Span
+	// Category is the kind of room it is, once the plan is categorized, see Plan.Categorize.
+	Category string
+}
+
+// roomTotals counts the chairs of all the rooms, by type: the totals of a plan, a floor, an apartment or a category.
+func roomTotals(rooms []*Room) map[rune]int {
+	totals := map[rune]int{}
+	for _, room := range rooms {
+		for chairType, count := range room.Chairs {
+			totals[chairType] += count
+		}
+	}
+	return totals
+}
+
+// groupString shows the totals of a group of rooms, like an apartment or a category, then its rooms:
+//
+// apartment 3B:
+// W: 3, P: 2, S: 0, C: 0
+// rooms: bathroom, kitchen, living room
+func groupString(kind, name string, rooms []*Room) string {
+	names := make([]string, 0, len(rooms))
+	for _, room := range rooms {
+		names = append(names, displayName(room.Name))
+	}
+	return fmt.Sprintf("%s %s:\n%s\nrooms: %s", kind, name, ChairCounts(roomTotals(rooms)), strings.Join(names, ", "))
+}
+
+func (d *roomData) room() *Room {
+	chairs := make(map[rune]int, len(d.Chairs))
+	for chairType, count := range d.Chairs {
+		chairs[chairType] = count
+	}
+	return &Room{
+		Name:       floorRoomName(d.floor, d.Name),
+		Chairs:     chairs,
+		Cells:      d.cells,
+		Placements: d.chairs,
+		Title:      d.title,
+		Floor:      d.floor,
+		Apartment:  d.apartment,
+		Label:      d.label,
+	}
+}
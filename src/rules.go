@@ -0,0 +1,185 @@
+package src
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// severities of the findings of placement rules
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// PlacementRule says what chairs make sense in some rooms:
+// the rooms of a category, or the rooms whose name matches a regular expression, or both.
+// Each limit is only checked if it's set.
+type PlacementRule struct {
+	// Name tells the rule apart in findings.
+	Name     string `json:"name"`
+	Category string `json:"category,omitempty"`
+	// Room is a regular expression the room's name, without its floor, must match, regardless of case.
+	Room string `json:"room,omitempty"`
+	// Allowed are the letters of the only chair types the rooms can hold.
+	Allowed []string `json:"allowed,omitempty"`
+	// Min and Max are how many chairs the rooms can hold, all types together.
+	Min *int `json:"min,omitempty"`
+	Max *int `json:"max,omitempty"`
+	// MaxPer100Cells is how many chairs the rooms can hold for every 100 cells of floor.
+	MaxPer100Cells float64 `json:"max_per_100_cells,omitempty"`
+	// Severity is SeverityError, the default, or SeverityWarning.
+	Severity string `json:"severity,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// Rules are the placement rules the rooms of plans are checked against.
+type Rules struct {
+	Rules []PlacementRule `json:"rules"`
+}
+
+// ReadRules decodes Rules from JSON:
+//
+//	{"rules": [
+//	  {"name": "no sofa in the toilet", "category": "bathroom", "allowed": ["W", "P"]},
+//	  {"name": "closets are small", "room": "closet", "max": 2, "severity": "warning"},
+//	  {"name": "room to move", "max_per_100_cells": 8}
+//	]}
+func ReadRules(reader io.Reader) (*Rules, error) {
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	rules := &Rules{}
+	if err := decoder.Decode(rules); err != nil {
+		return nil, fmt.Errorf("can't decode the rules: %w", err)
+	}
+	for i := range rules.Rules {
+		rule := &rules.Rules[i]
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rules: rule %d has no name", i+1)
+		}
+		for _, code := range rule.Allowed {
+			if len([]rune(code)) != 1 || !IsChair([]rune(code)[0]) {
+				return nil, fmt.Errorf("rules: %s: %q is not a chair type", rule.Name, code)
+			}
+		}
+		if rule.Min != nil && *rule.Min < 0 || rule.Max != nil && *rule.Max < 0 || rule.MaxPer100Cells < 0 {
+			return nil, fmt.Errorf("rules: %s: negative limit", rule.Name)
+		}
+		if rule.Min != nil && rule.Max != nil && *rule.Min > *rule.Max {
+			return nil, fmt.Errorf("rules: %s: min %d is more than max %d", rule.Name, *rule.Min, *rule.Max)
+		}
+		switch rule.Severity {
+		case "":
+			rule.Severity = SeverityError
+		case SeverityError, SeverityWarning:
+		default:
+			return nil, fmt.Errorf("rules: %s: severity %q is neither %s nor %s", rule.Name, rule.Severity, SeverityError, SeverityWarning)
+		}
+		if rule.Room != "" {
+			pattern, err := regexp.Compile("(?i)" + rule.Room)
+			if err != nil {
+				return nil, fmt.Errorf("rules: %s: %w", rule.Name, err)
+			}
+			rule.pattern = pattern
+		}
+	}
+	return rules, nil
+}
+
+// UsesCategories tells whether any rule picks rooms by category: those only match rooms of a categorized plan.
+func (r *Rules) UsesCategories() bool {
+	for _, rule := range r.Rules {
+		if rule.Category != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Finding is a rule a room breaks, and where.
+type Finding struct {
+	ParseError
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	Room     string `json:"room"`
+}
+
+// Check lists where the closed rooms of the plan break the rules, in the order of the plan's lines.
+// A chair of a type that's not allowed is found at the chair, the other limits at the room's title,
+// or its first cell if it has no title.
+func (r *Rules) Check(plan *Plan) []*Finding {
+	findings := []*Finding{}
+	for _, room := range plan.Rooms {
+		at := room.Title.Position
+		if room.Title.Length == 0 && len(room.Cells) > 0 {
+			at = room.Cells[0]
+		}
+		for _, rule := range r.Rules {
+			if !rule.matches(room) {
+				continue
+			}
+			found := func(pos Position, format string, args ...any) {
+				findings = append(findings, &Finding{
+					ParseError: ParseError{Position: pos, Message: fmt.Sprintf(format, args...) + " (" + rule.Name + ")"},
+					Severity:   rule.Severity,
+					Rule:       rule.Name,
+					Room:       room.Name,
+				})
+			}
+
+			if len(rule.Allowed) > 0 {
+				for _, chair := range room.Placements {
+					chairType, _ := ChairTypeOf(chair.Type)
+					if !rule.allows(chair.Type) {
+						found(chair.Position, "%s not allowed in %s", chairType.Name, displayName(room.Name))
+					}
+				}
+			}
+			chairs := 0
+			for _, count := range room.Chairs {
+				chairs += count
+			}
+			if rule.Min != nil && chairs < *rule.Min {
+				found(at, "%s has %d chairs, at least %d wanted", displayName(room.Name), chairs, *rule.Min)
+			}
+			if rule.Max != nil && chairs > *rule.Max {
+				found(at, "%s has %d chairs, at most %d allowed", displayName(room.Name), chairs, *rule.Max)
+			}
+			// the room's density is worked out rather than the limit scaled to the room: a room right at the limit
+			// must not go over it on a rounding error, 18.4 * 375 / 100 being 68.99999999999999
+			if rule.MaxPer100Cells > 0 && len(room.Cells) > 0 &&
+				float64(chairs)*100/float64(len(room.Cells)) > rule.MaxPer100Cells {
+				found(at, "%s has %d chairs in %d cells, more than %g per 100 cells",
+					displayName(room.Name), chairs, len(room.Cells), rule.MaxPer100Cells)
+			}
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i].Position, findings[j].Position
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return findings
+}
+
+func (rule *PlacementRule) matches(room *Room) bool {
+	if rule.Category != "" && !strings.EqualFold(rule.Category, room.Category) {
+		return false
+	}
+	return rule.pattern == nil || rule.pattern.MatchString(room.localName())
+}
+
+func (rule *PlacementRule) allows(chairType rune) bool {
+	for _, code := range rule.Allowed {
+		if code == string(chairType) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package src
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRules_Check(t *testing.T) {
+	rules, err := ReadRules(strings.NewReader(`{"rules": [
+		{"name": "toilets", "category": "bathroom", "allowed": ["W"]},
+		{"name": "closets", "room": "^closet", "max": 1, "severity": "warning"},
+		{"name": "offices", "room": "office", "min": 2},
+		{"name": "crowds", "max_per_100_cells": 10}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := ParsePlan(strings.NewReader(`+----------+--------+----------+
+|(toilet)  |(closet)|(office)  |
+| S   W  C |  P P   |   W      |
++----------+--------+----------+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan.Categorize(&Categories{Rules: []CategoryRule{{Category: "bathroom", Exact: "toilet"}}})
+
+	var got []string
+	for _, finding := range rules.Check(plan) {
+		got = append(got, finding.Position.String()+": "+finding.Severity+": "+finding.Message)
+	}
+	want := []string{
+		"line 2, column 2: error: toilet has 3 chairs in 20 cells, more than 10 per 100 cells (crowds)",
+		"line 2, column 13: warning: closet has 2 chairs, at most 1 allowed (closets)",
+		"line 2, column 13: error: closet has 2 chairs in 16 cells, more than 10 per 100 cells (crowds)",
+		"line 2, column 22: error: office has 1 chairs, at least 2 wanted (offices)",
+		"line 3, column 3: error: sofa chair not allowed in toilet (toilets)",
+		"line 3, column 10: error: china chair not allowed in toilet (toilets)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Check() =\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestRules_CheckAtTheLimit(t *testing.T) {
+	rules, err := ReadRules(strings.NewReader(`{"rules": [{"name": "crowds", "max_per_100_cells": 18.4}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for chairs, want := range map[int]int{69: 0, 70: 1} {
+		plan := &Plan{Rooms: []*Room{{Name: "hall", Chairs: map[rune]int{'W': chairs}, Cells: make([]Position, 375)}}}
+		if got := len(rules.Check(plan)); got != want {
+			t.Errorf("Check() of %d chairs in 375 cells found %d rules broken, want %d", chairs, got, want)
+		}
+	}
+}
+
+func TestReadRules_Errors(t *testing.T) {
+	for config, want := range map[string]string{
+		`{"rules": [{"max": 1}]}`:                         "rules: rule 1 has no name",
+		`{"rules": [{"name": "a", "allowed": ["X"]}]}`:    `rules: a: "X" is not a chair type`,
+		`{"rules": [{"name": "a", "min": 3, "max": 2}]}`:  "rules: a: min 3 is more than max 2",
+		`{"rules": [{"name": "a", "max": -1}]}`:           "rules: a: negative limit",
+		`{"rules": [{"name": "a", "severity": "fatal"}]}`: `rules: a: severity "fatal" is neither error nor warning`,
+		`{"rules": [{"name": "a", "room": "("}]}`:         "rules: a: error parsing regexp: missing closing ): `(?i)(`",
+		`{"rules": [{"name": "a", "max_chairs": 1}]}`:     `can't decode the rules: json: unknown field "max_chairs"`,
+	} {
+		if _, err := ReadRules(strings.NewReader(config)); err == nil || err.Error() != want {
+			t.Errorf("ReadRules(%s) error = %v, want %s", config, err, want)
+		}
+	}
+}
@@ -33,6 +33,14 @@ func (s *segment) IsInSet(set LineSegments) bool {
 
 type LineSegments []*segment
 
+func (ls LineSegments) String() string {
+	strs := make([]string, 0, len(ls))
+	for _, s := range ls {
+		strs = append(strs, s.String())
+	}
+	return strings.Join(strs, " ")
+}
+
 // NewLineSegments is a constructor for LineSegments.
 // It accepts initial segments as variadic params.
 func NewLineSegments(segments ...*segment) LineSegments {
@@ -43,17 +51,29 @@ func NewLineSegments(segments ...*segment) LineSegments {
 	return ls
 }
 
-func Split(line string) LineSegments {
-	delimiters := map[string]struct{}{
-		"|": {}, "\\": {}, "/": {}, "+": {}, "-": {},
+// IsWall tells whether c is one of the characters walls are drawn with, doors included.
+// These are also the delimiters Split cuts lines at.
+func IsWall(c rune) bool {
+	switch c {
+	case '|', '\\', '/', '+', '-', '#':
+		return true
 	}
+	return false
+}
+
+// IsDoor tells whether c is a door: a wall the parser sweeps around like any other,
+// but one that connects the rooms on both sides of it into an apartment.
+func IsDoor(c rune) bool {
+	return c == '#'
+}
 
+func Split(line string) LineSegments {
 	segments := NewLineSegments()
 	start := -1
 	var foundFirstDelimiter bool
 
 	for i, c := range line {
-		if _, exists := delimiters[string(c)]; exists {
+		if IsWall(c) {
 			if start >= 0 && foundFirstDelimiter && i > start {
 				segment := &segment{start, strings.Trim(line[start:i], "+-")}
 				segments = append(segments, segment)
@@ -90,11 +110,14 @@ func Overlaps(start, length int, set LineSegments) LineSegments {
 
 // MultipleOverlaps finds the segments from set2 that overlap with any of the segments in set1.
 // nonOverlappingSegments will be the rest.
+// A segment of set2 is reported only once, even when it overlaps several segments of set1.
 func MultipleOverlaps(set1, set2 LineSegments) (overlappingSegments, nonOverlappingSegments LineSegments) {
 	for _, s2 := range set2 {
 		for _, s1 := range set1 {
-			over := s1.Overlaps(NewLineSegments(s2))
-			overlappingSegments = append(overlappingSegments, over...)
+			if len(s1.Overlaps(NewLineSegments(s2))) > 0 {
+				overlappingSegments = append(overlappingSegments, s2)
+				break
+			}
 		}
 	}
 	nonOverlappingSegments = segmentsDiff(set2, overlappingSegments)
@@ -118,31 +141,63 @@ func segmentsDiff(set1, set2 LineSegments) (result LineSegments) {
 	return
 }
 
-// sendData looks for title and chairs inside a string
+// sendData looks for title, apartment label and chairs inside a string.
+// The positions it records are relative to the string: line 0, column = byte offset.
 func segmentData(str string) (*roomData, error) {
-	var roomTitle string
+	var roomTitle, label string
 	roomData := newRoomData()
 
-	stillInsideTitle := false
-	for _, c := range str {
+	stillInsideTitle, stillInsideLabel := false, false
+	for i, c := range str {
 		switch {
+		case c == '[' && !stillInsideTitle:
+			stillInsideLabel = true
+			roomData.label = Span{Position: Position{Column: i}}
+		case c == ']' && stillInsideLabel:
+			stillInsideLabel = false
+			roomData.label.Length = i - roomData.label.Column + 1
+			apartment, found := apartmentLabel(label)
+			if !found {
+				return nil, &ParseError{
+					Position: roomData.label.Position,
+					Message:  fmt.Sprintf("label [%s] is not an apartment label like [apt 3B]", label),
+				}
+			}
+			roomData.apartment = apartment
+		case stillInsideLabel:
+			label += string(c)
 		case c == '(':
 			stillInsideTitle = true
+			roomData.title = Span{Position: Position{Column: i}}
 		case c == ')':
 			stillInsideTitle = false
 			roomData.Name = strings.TrimSpace(roomTitle)
+			roomData.title.Length = i - roomData.title.Column + 1
 		case stillInsideTitle:
 			roomTitle += string(c)
-		case c == 'W', c == 'P', c == 'S', c == 'C':
+		case IsChair(c):
 			roomData.Chairs[c]++
+			roomData.chairs = append(roomData.chairs, Chair{Type: c, Position: Position{Column: i}})
 		case c == ' ':
 		default:
-			return nil, fmt.Errorf("strange character encountered: %c", c)
+			return nil, &ParseError{
+				Position: Position{Column: i},
+				Message:  fmt.Sprintf("strange character encountered: %c", c),
+			}
 		}
 	}
 
+	if stillInsideLabel {
+		return nil, &ParseError{
+			Position: roomData.label.Position,
+			Message:  fmt.Sprintf("apartment label did not close. It starts with '%s'", label),
+		}
+	}
 	if stillInsideTitle {
-		return nil, fmt.Errorf("room title did not close. It starts with '%s'", roomTitle)
+		return nil, &ParseError{
+			Position: roomData.title.Position,
+			Message:  fmt.Sprintf("room title did not close. It starts with '%s'", roomTitle),
+		}
 	}
 
 	return roomData, nil
@@ -0,0 +1,91 @@
+package src
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DefaultMaxPlanSize is the biggest plan the HTTP API accepts by default, in bytes.
+const DefaultMaxPlanSize = 1 << 20
+
+// errorResponse is what the HTTP API answers with when it can't parse a plan.
+type errorResponse struct {
+	Errors []*ParseError `json:"errors"`
+}
+
+// validationResponse is what the HTTP API answers a validation with.
+type validationResponse struct {
+	Valid    bool          `json:"valid"`
+	Problems []*ParseError `json:"problems"`
+}
+
+// NewServer returns the HTTP API for checking plans, each request getting a parser of its own:
+//   - POST /parse takes a plan as the request body and answers with its Result,
+//     or with a 422 and the errors that stopped the parser,
+//   - POST /validate answers with whether the plan is valid and the problems found in it,
+//   - GET /healthz answers 200 as long as the server is up.
+//
+// Plans bigger than maxPlanSize bytes are turned down with a 413.
+func NewServer(maxPlanSize int64) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /parse", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := readPlanBody(w, r, maxPlanSize)
+		if !ok {
+			return
+		}
+		plan, err := ParsePlan(bytes.NewReader(body))
+		if err != nil {
+			writeJSON(w, http.StatusUnprocessableEntity, errorResponse{Errors: []*ParseError{AsParseError(err)}})
+			return
+		}
+		writeJSON(w, http.StatusOK, plan.Result())
+	})
+
+	mux.HandleFunc("POST /validate", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := readPlanBody(w, r, maxPlanSize)
+		if !ok {
+			return
+		}
+		problems := Validate(bytes.NewReader(body))
+		writeJSON(w, http.StatusOK, validationResponse{Valid: len(problems) == 0, Problems: problems})
+	})
+
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = io.WriteString(w, "ok\n")
+	})
+
+	return mux
+}
+
+// readPlanBody reads the whole plan off the request, unless it's too big.
+// If it can't, it answers the request itself and returns false.
+func readPlanBody(w http.ResponseWriter, r *http.Request, maxPlanSize int64) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPlanSize))
+	if err == nil {
+		return body, true
+	}
+
+	var tooBig *http.MaxBytesError
+	if errors.As(err, &tooBig) {
+		writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse{Errors: []*ParseError{
+			{Message: fmt.Sprintf("the plan is bigger than %d bytes", tooBig.Limit)},
+		}})
+		return nil, false
+	}
+	writeJSON(w, http.StatusBadRequest, errorResponse{Errors: []*ParseError{
+		{Message: fmt.Sprintf("can't read the plan: %v", err)},
+	}})
+	return nil, false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
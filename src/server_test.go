@@ -0,0 +1,112 @@
+package src
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServer(t *testing.T) {
+	server := httptest.NewServer(NewServer(64))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "parse",
+			method:     http.MethodPost,
+			path:       "/parse",
+			body:       "+------+\n|(a) W |\n+------+\n",
+			wantStatus: http.StatusOK,
+			wantBody: `{"total":{"C":0,"P":0,"S":0,"W":1},"rooms":[{"name":"a","chairs":{"C":0,"P":0,"S":0,"W":1},` +
+				`"area":6,"placements":[{"type":"W","line":2,"column":6}]}]}`,
+		},
+		{
+			name:       "parse error",
+			method:     http.MethodPost,
+			path:       "/parse",
+			body:       "+------+\n|(a) X |\n+------+\n",
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   `{"errors":[{"line":2,"column":6,"message":"strange character encountered: X"}]}`,
+		},
+		{
+			name:       "plan too big",
+			method:     http.MethodPost,
+			path:       "/parse",
+			body:       strings.Repeat("+------+\n", 10),
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantBody:   `{"errors":[{"line":0,"column":0,"message":"the plan is bigger than 64 bytes"}]}`,
+		},
+		{
+			name:       "valid",
+			method:     http.MethodPost,
+			path:       "/validate",
+			body:       "+------+\n|(a) W |\n+------+\n",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"valid":true,"problems":[]}`,
+		},
+		{
+			name:       "unclosed room",
+			method:     http.MethodPost,
+			path:       "/validate",
+			body:       "+------+\n|(a) W |\n|      |\n",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"valid":false,"problems":[{"line":2,"column":2,"message":"room a never closes"}]}`,
+		},
+		{
+			name:       "title that doesn't close",
+			method:     http.MethodPost,
+			path:       "/validate",
+			body:       "+------+\n| W (a |\n+------+\n",
+			wantStatus: http.StatusOK,
+			wantBody: `{"valid":false,"problems":[{"line":2,"column":5,` +
+				`"message":"room title did not close. It starts with 'a '"}]}`,
+		},
+		{
+			name:       "health",
+			method:     http.MethodGet,
+			path:       "/healthz",
+			wantStatus: http.StatusOK,
+			wantBody:   "ok",
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodGet,
+			path:       "/parse",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := http.NewRequest(tt.method, server.URL+tt.path, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer response.Body.Close()
+			body, err := io.ReadAll(response.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if response.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", response.StatusCode, tt.wantStatus)
+			}
+			if got := strings.TrimSpace(string(body)); tt.wantBody != "" && got != tt.wantBody {
+				t.Errorf("body =\n%s\nwant:\n%s", got, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package src
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Snapshot is the state of a FlatParser between two lines:
+// the open rooms with the segments they were last seen with, and the rooms closed so far.
+// Restoring it lets a parser pick up right after the line it was taken at.
+type Snapshot struct {
+	line        int
+	openRooms   []*openRoom
+	closedRooms []*roomData
+	opened      int
+	floor       string
+	floors      []Storey
+	unclosed    []*roomData
+}
+
+// Line is the number of lines the parser had ingested when the snapshot was taken.
+func (s *Snapshot) Line() int {
+	return s.line
+}
+
+// Snapshot takes the parser's current state.
+// Ingesting further lines doesn't change the snapshot.
+func (p *FlatParser) Snapshot() *Snapshot {
+	return &Snapshot{
+		line:        p.Line,
+		openRooms:   cloneOpenRooms(p.OpenRooms),
+		closedRooms: slices.Clone(p.closedRooms),
+		opened:      p.opened,
+		floor:       p.floor,
+		floors:      slices.Clone(p.floors),
+		unclosed:    slices.Clone(p.unclosed),
+	}
+}
+
+// Restore puts the parser back in the state it was in when s was taken.
+// The snapshot stays as it was, so it can be restored again later.
+// The parser's Logger and callbacks are kept.
+func (p *FlatParser) Restore(s *Snapshot) {
+	p.Line = s.line
+	p.OpenRooms = cloneOpenRooms(s.openRooms)
+	p.closedRooms = slices.Clone(s.closedRooms)
+	p.opened = s.opened
+	p.floor = s.floor
+	p.floors = slices.Clone(s.floors)
+	p.unclosed = slices.Clone(s.unclosed)
+}
+
+// cloneOpenRooms copies the open rooms deep enough for the copies to be ingested into
+// without touching the originals. Closed rooms don't change anymore, so they don't need this.
+func cloneOpenRooms(rooms []*openRoom) []*openRoom {
+	clones := make([]*openRoom, 0, len(rooms))
+	for _, room := range rooms {
+		clones = append(clones, &openRoom{
+			RoomData: room.RoomData.clone(),
+			segments: room.segments,
+			id:       room.id,
+		})
+	}
+	return clones
+}
+
+// clone copies the room data. The slices are clipped rather than copied:
+// appending to the clone's then reallocates instead of writing over what the original appends.
+func (d *roomData) clone() *roomData {
+	return &roomData{
+		Name:      d.Name,
+		Chairs:    maps.Clone(d.Chairs),
+		cells:     slices.Clip(d.cells),
+		chairs:    slices.Clip(d.chairs),
+		title:     d.title,
+		floor:     d.floor,
+		apartment: d.apartment,
+		label:     d.label,
+	}
+}
+
+// Document is a plan that's being edited, the way an editor integration sees it.
+// It keeps a snapshot of the parser after every line, so after an edit
+// only the lines from the first changed one onward are parsed again.
+type Document struct {
+	lines []string
+	// snapshots[i] is the parser's state after the first i lines.
+	// Lines after one the parser gave up on have none.
+	snapshots []*Snapshot
+	// what stopped the parser, if anything
+	err error
+}
+
+func NewDocument() *Document {
+	return &Document{snapshots: []*Snapshot{NewRoomParser().Snapshot()}}
+}
+
+// Update replaces the document's text, and parses it again from the first line that changed.
+// It returns how many lines were reused from the previous parse.
+func (d *Document) Update(text string) int {
+	lines := splitLines(text)
+
+	reused := 0
+	for reused < len(lines) && reused < len(d.lines) && reused < len(d.snapshots)-1 && lines[reused] == d.lines[reused] {
+		reused++
+	}
+
+	parser := NewRoomParser()
+	parser.Restore(d.snapshots[reused])
+	d.lines = lines
+	d.snapshots = d.snapshots[:reused+1]
+	d.err = nil
+
+	for _, line := range lines[reused:] {
+		if err := parser.Ingest(line); err != nil {
+			d.err = fmt.Errorf("[line %d] error parsing line: %w", parser.Line, err)
+			break
+		}
+		d.snapshots = append(d.snapshots, parser.Snapshot())
+	}
+
+	return reused
+}
+
+// Plan returns what the parser found in the document.
+// If it gave up on a line, the plan has what was found before that line, and the error comes along.
+func (d *Document) Plan() (*Plan, error) {
+	parser := NewRoomParser()
+	parser.Restore(d.snapshots[len(d.snapshots)-1])
+	plan := parser.Plan()
+	plan.Lines = d.lines
+	return plan, d.err
+}
+
+// splitLines cuts text into lines the way ReadPlan reads them:
+// without line endings, and with no empty line after a trailing newline.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, "\r")
+	}
+	return lines
+}
@@ -0,0 +1,133 @@
+package src
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDocument_Update(t *testing.T) {
+	content, err := os.ReadFile("../rooms.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := string(content)
+
+	// replaceLine replaces the given 1-based line of text
+	replaceLine := func(text string, line int, replacement string) string {
+		lines := strings.Split(text, "\n")
+		lines[line-1] = replacement
+		return strings.Join(lines, "\n")
+	}
+	lineCount := len(splitLines(original))
+
+	tests := []struct {
+		name       string
+		text       string
+		wantReused int
+		wantErr    string
+	}{
+		{
+			name:       "first parse",
+			text:       original,
+			wantReused: 0,
+		},
+		{
+			name:       "chair changed",
+			text:       replaceLine(original, 20, "|   S          |           |                     |"),
+			wantReused: 19,
+		},
+		{
+			name:       "wall broken",
+			text:       replaceLine(original, 17, "+------ -------+           |                     |"),
+			wantReused: 16,
+		},
+		{
+			name:       "strange character",
+			text:       replaceLine(original, 26, "| (bathroom) X |           |      (kitchen)      |"),
+			wantReused: 16,
+			wantErr:    "[line 26] error parsing line: error ingesting segments: [segment: ' (bathroom) X '] error parsing segment: strange character encountered: X",
+		},
+		{
+			name:       "strange character removed",
+			text:       original,
+			wantReused: 25,
+		},
+		{
+			name:       "last line removed",
+			text:       strings.TrimSuffix(original, "\n"),
+			wantReused: lineCount - 1,
+		},
+		{
+			name:       "line inserted at the top",
+			text:       "\n" + original,
+			wantReused: 0,
+		},
+		{
+			name:       "windows line endings",
+			text:       strings.ReplaceAll(original, "\n", "\r\n"),
+			wantReused: 0,
+		},
+	}
+
+	document := NewDocument()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if reused := document.Update(tt.text); reused != tt.wantReused {
+				t.Errorf("Update() reused %d lines, want %d", reused, tt.wantReused)
+			}
+
+			got, err := document.Plan()
+			if err != nil && err.Error() != tt.wantErr || err == nil && tt.wantErr != "" {
+				t.Fatalf("Plan() error = %v, want %q", err, tt.wantErr)
+			}
+
+			parser := NewRoomParser()
+			want, err := parser.ReadPlan(strings.NewReader(tt.text))
+			if err != nil {
+				if err.Error() != tt.wantErr {
+					t.Fatalf("full parse error = %v, want %q", err, tt.wantErr)
+				}
+				// the document keeps what was found before the line the parser gave up on
+				lines := splitLines(tt.text)
+				want, err = ParsePlan(strings.NewReader(strings.Join(lines[:parser.Line-1], "\n")))
+				if err != nil {
+					t.Fatal(err)
+				}
+				want.Lines = lines
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("incremental parse differs from a full one:\n%v\nwant:\n%v", got, want)
+			}
+		})
+	}
+}
+
+func TestFlatParser_Restore(t *testing.T) {
+	lines := strings.Split("+---+---+\n|(a)| W |\n+---+ P |\n    +---+", "\n")
+
+	parser := NewRoomParser()
+	snapshots := []*Snapshot{parser.Snapshot()}
+	for _, line := range lines {
+		if err := parser.Ingest(line); err != nil {
+			t.Fatal(err)
+		}
+		snapshots = append(snapshots, parser.Snapshot())
+	}
+	want := parser.String()
+
+	// resuming from any line, even more than once, ends up where the full parse did
+	for _, snapshot := range append(snapshots, snapshots...) {
+		resumed := NewRoomParser()
+		resumed.Restore(snapshot)
+		for _, line := range lines[snapshot.Line():] {
+			if err := resumed.Ingest(line); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := resumed.String(); got != want {
+			t.Errorf("resumed after line %d:\n%s\nwant:\n%s", snapshot.Line(), got, want)
+		}
+	}
+}
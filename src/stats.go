@@ -0,0 +1,174 @@
+package src
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"text/tabwriter"
+)
+
+// DefaultMaxDensity is how many chairs a room can hold for every 100 cells of floor before it's overcrowded.
+const DefaultMaxDensity = 10.0
+
+// Stats sums up the size of a plan and what's in it.
+// Chair counts are keyed by type letter and list every type of the catalog.
+type Stats struct {
+	Lines   int `json:"lines"`
+	Columns int `json:"columns"`
+	// Area is how many floor cells the closed rooms have together.
+	Area     int            `json:"area"`
+	Chairs   map[string]int `json:"chairs"`
+	Unclosed int            `json:"unclosed"`
+	// MaxDensity is the density above which rooms are overcrowded, in chairs per 100 cells.
+	MaxDensity float64 `json:"max_density"`
+	// Floors sum up each floor, for building plans with floor headers.
+	Floors []GroupStats `json:"floors,omitempty"`
+	// Apartments sum up each apartment, for plans with apartment labels.
+	Apartments []GroupStats `json:"apartments,omitempty"`
+	// Categories sum up each room category, for categorized plans.
+	Categories []GroupStats `json:"categories,omitempty"`
+	Rooms      []RoomStats  `json:"rooms"`
+}
+
+// GroupStats sums up a group of rooms of Stats: a floor, an apartment or a room category.
+type GroupStats struct {
+	Name   string         `json:"name"`
+	Rooms  int            `json:"rooms"`
+	Area   int            `json:"area"`
+	Chairs map[string]int `json:"chairs"`
+}
+
+func groupStats(name string, rooms []*Room) GroupStats {
+	stats := GroupStats{Name: name, Rooms: len(rooms), Chairs: catalogCounts(roomTotals(rooms))}
+	for _, room := range rooms {
+		stats.Area += len(room.Cells)
+	}
+	return stats
+}
+
+// RoomStats is a room of Stats.
+type RoomStats struct {
+	Name   string         `json:"name"`
+	Area   int            `json:"area"`
+	Chairs map[string]int `json:"chairs"`
+	// Free is how many cells of the room have no chair on them.
+	Free int `json:"free_cells"`
+	// Density is how many chairs the room holds for every 100 cells, rounded to two decimals.
+	Density     float64 `json:"density"`
+	Overcrowded bool    `json:"overcrowded"`
+}
+
+// density is Density before rounding, what the room is flagged by. It's worked out from the area and the chairs,
+// so stats decoded from JSON or put together by hand are flagged the same.
+func (r *RoomStats) density() float64 {
+	if r.Area == 0 {
+		return 0
+	}
+	return float64(sumCounts(r.Chairs)) * 100 / float64(r.Area)
+}
+
+// Stats sums up the plan. The rooms are sorted by name, like the plan's,
+// and the ones denser than DefaultMaxDensity are flagged as overcrowded.
+func (p *Plan) Stats() *Stats {
+	stats := &Stats{
+		Lines:    len(p.Lines),
+		Chairs:   catalogCounts(p.Totals()),
+		Unclosed: len(p.Unclosed),
+		Rooms:    []RoomStats{},
+	}
+	for _, line := range p.Lines {
+		stats.Columns = max(stats.Columns, len(line))
+	}
+	for _, room := range p.Rooms {
+		stats.Area += len(room.Cells)
+		chairs := len(room.Placements)
+		roomStats := RoomStats{Name: room.Name, Area: len(room.Cells), Chairs: catalogCounts(room.Chairs), Free: len(room.Cells) - chairs}
+		roomStats.Density = math.Round(roomStats.density()*100) / 100
+		stats.Rooms = append(stats.Rooms, roomStats)
+	}
+	stats.FlagOvercrowded(DefaultMaxDensity)
+	for _, floor := range p.Floors {
+		stats.Floors = append(stats.Floors, groupStats(floor.Name, p.floorRooms(floor.Name)))
+	}
+	for _, apartment := range p.Apartments() {
+		stats.Apartments = append(stats.Apartments, groupStats(apartment.Name, apartment.Rooms))
+	}
+	for _, category := range p.Categories() {
+		stats.Categories = append(stats.Categories, groupStats(category.Name, category.Rooms))
+	}
+	return stats
+}
+
+// FlagOvercrowded flags the rooms denser than maxDensity, in chairs per 100 cells, as overcrowded.
+func (s *Stats) FlagOvercrowded(maxDensity float64) {
+	s.MaxDensity = maxDensity
+	for i := range s.Rooms {
+		s.Rooms[i].Overcrowded = s.Rooms[i].density() > maxDensity
+	}
+}
+
+// String shows the stats as a summary, a line per floor, apartment and room category if there are any,
+// then a table of the rooms, one column per chair type, the density in chairs per 100 cells:
+//
+//	size: 52 lines, 50 columns
+//	rooms: 9, floor area: 1372 cells, unclosed rooms: 0
+//	chairs: 31 (W: 14, P: 7, S: 3, C: 1)
+//	floor 1: rooms: 5, floor area: 702 cells, chairs: 16 (W: 8, P: 4, S: 3, C: 1)
+//	overcrowded rooms: 1 (more than 10 chairs per 100 cells)
+//
+//	room     area  free  density  W  P  S  C
+//	balcony  75    73    2.67     0  2  0  0
+//	closet   27    24    11.11    0  3  0  0  overcrowded
+func (s *Stats) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "size: %d lines, %d columns\n", s.Lines, s.Columns)
+	fmt.Fprintf(&b, "rooms: %d, floor area: %d cells, unclosed rooms: %d\n", len(s.Rooms), s.Area, s.Unclosed)
+	fmt.Fprintf(&b, "chairs: %d (%s)\n", sumCounts(s.Chairs), formatCounts(s.Chairs))
+	for _, groups := range []struct {
+		kind  string
+		stats []GroupStats
+	}{{"floor", s.Floors}, {"apartment", s.Apartments}, {"category", s.Categories}} {
+		for _, group := range groups.stats {
+			fmt.Fprintf(&b, "%s %s: rooms: %d, floor area: %d cells, chairs: %d (%s)\n",
+				groups.kind, group.Name, group.Rooms, group.Area, sumCounts(group.Chairs), formatCounts(group.Chairs))
+		}
+	}
+	if len(s.Rooms) == 0 {
+		return b.String()
+	}
+	overcrowded := 0
+	for _, room := range s.Rooms {
+		if room.Overcrowded {
+			overcrowded++
+		}
+	}
+	fmt.Fprintf(&b, "overcrowded rooms: %d (more than %g chairs per 100 cells)\n", overcrowded, s.MaxDensity)
+
+	b.WriteString("\n")
+	table := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprint(table, "room\tarea\tfree\tdensity")
+	for _, chairType := range ChairTypes {
+		fmt.Fprintf(table, "\t%c", chairType.Code)
+	}
+	fmt.Fprintln(table)
+	for _, room := range s.Rooms {
+		fmt.Fprintf(table, "%s\t%d\t%d\t%.2f", displayName(room.Name), room.Area, room.Free, room.Density)
+		for _, chairType := range ChairTypes {
+			fmt.Fprintf(table, "\t%d", room.Chairs[string(chairType.Code)])
+		}
+		if room.Overcrowded {
+			fmt.Fprint(table, "\tovercrowded")
+		}
+		fmt.Fprintln(table)
+	}
+	_ = table.Flush()
+	return b.String()
+}
+
+func sumCounts(counts map[string]int) int {
+	sum := 0
+	for _, count := range counts {
+		sum += count
+	}
+	return sum
+}
@@ -0,0 +1,125 @@
+package src
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestPlan_Stats(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name: "rooms",
+			input: `+-----+-------+
+|(a) W|(b)    |
+|   W +---+ P |
++-----+   |   |
+      +---+---+`,
+			want: `size: 5 lines, 15 columns
+rooms: 3, floor area: 26 cells, unclosed rooms: 0
+chairs: 3 (W: 2, P: 1, S: 0, C: 0)
+overcrowded rooms: 1 (more than 10 chairs per 100 cells)
+
+room       area  free  density  W  P  S  C
+(no name)  3     3     0.00     0  0  0  0
+a          10    8     20.00    2  0  0  0  overcrowded
+b          13    12    7.69     0  1  0  0
+`,
+		},
+		{
+			name:  "no rooms",
+			input: "+---+\n|(a)|\n",
+			want: `size: 2 lines, 5 columns
+rooms: 0, floor area: 0 cells, unclosed rooms: 1
+chairs: 0 (W: 0, P: 0, S: 0, C: 0)
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := ParsePlan(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := plan.Stats().String(); got != tt.want {
+				t.Errorf("Stats():\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStats_FlagOvercrowded(t *testing.T) {
+	plan, err := ParsePlan(strings.NewReader(`+-----+-------+
+|(a) W|(b)    |
+|   W +---+ P |
++-----+   |   |
+      +---+---+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := plan.Stats()
+	stats.FlagOvercrowded(5)
+	for _, tt := range []struct {
+		room        string
+		free        int
+		density     float64
+		overcrowded bool
+	}{
+		{"", 3, 0, false},
+		{"a", 8, 20, true},
+		{"b", 12, 7.69, true},
+	} {
+		i := sort.Search(len(stats.Rooms), func(i int) bool { return stats.Rooms[i].Name >= tt.room })
+		room := stats.Rooms[i]
+		if room.Free != tt.free || room.Density != tt.density || room.Overcrowded != tt.overcrowded {
+			t.Errorf("room %q: free %d, density %v, overcrowded %v, want %d, %v, %v",
+				tt.room, room.Free, room.Density, room.Overcrowded, tt.free, tt.density, tt.overcrowded)
+		}
+	}
+	if stats.MaxDensity != 5 {
+		t.Errorf("MaxDensity = %v, want 5", stats.MaxDensity)
+	}
+}
+
+func TestStats_FlagOvercrowdedUnrounded(t *testing.T) {
+	// 1 chair in 9 cells is 11.111... per 100 cells, shown as 11.11
+	plan, err := ParsePlan(strings.NewReader("+---+\n|W  |\n|   |\n|   |\n+---+"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := plan.Stats()
+	if got := stats.Rooms[0].Density; got != 11.11 {
+		t.Fatalf("Density = %v, want 11.11", got)
+	}
+	stats.FlagOvercrowded(11.11)
+	if !stats.Rooms[0].Overcrowded {
+		t.Errorf("a room just over the limit is not overcrowded")
+	}
+	stats.FlagOvercrowded(11.12)
+	if stats.Rooms[0].Overcrowded {
+		t.Errorf("a room under the limit is overcrowded")
+	}
+}
+
+func TestStats_FlagOvercrowdedDecoded(t *testing.T) {
+	var stats Stats
+	if err := json.Unmarshal([]byte(`{"rooms": [
+		{"name": "hall", "area": 9, "chairs": {"W": 1, "P": 0, "S": 0, "C": 0}, "density": 11.11},
+		{"name": "empty", "area": 0, "chairs": {"W": 0, "P": 0, "S": 0, "C": 0}, "density": 0}
+	]}`), &stats); err != nil {
+		t.Fatal(err)
+	}
+	stats.FlagOvercrowded(11.11)
+	if !stats.Rooms[0].Overcrowded {
+		t.Errorf("a decoded room just over the limit is not overcrowded")
+	}
+	if stats.Rooms[1].Overcrowded {
+		t.Errorf("a decoded room without cells is overcrowded")
+	}
+}
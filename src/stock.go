@@ -0,0 +1,125 @@
+package src
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// StockLevel is what the factory has of a chair type.
+type StockLevel struct {
+	OnHand int
+	// Reserved chairs are on hand, but promised to someone else already.
+	Reserved int
+}
+
+// Stock holds the factory's stock levels, by chair type letter.
+type Stock map[rune]StockLevel
+
+// ReadStock reads stock levels from a CSV file with a header row and one row per chair type:
+//
+//	type,on_hand,reserved
+//	W,40,12
+//	plastic chair,10,0
+//
+// Chair types can be given by their letter or by their name in the catalog.
+func ReadStock(reader io.Reader) (Stock, error) {
+	rows, err := csv.NewReader(reader).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("can't read the stock: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("the stock is empty, not even a header")
+	}
+
+	columns := map[string]int{}
+	for i, name := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"type", "on_hand", "reserved"} {
+		if _, found := columns[name]; !found {
+			return nil, fmt.Errorf("the stock has no %s column", name)
+		}
+	}
+
+	stock := Stock{}
+	for i, row := range rows[1:] {
+		line := i + 2
+		chairType, found := chairTypeByLetterOrName(strings.TrimSpace(row[columns["type"]]))
+		if !found {
+			return nil, fmt.Errorf("[stock line %d] unknown chair type %q", line, row[columns["type"]])
+		}
+		if _, duplicate := stock[chairType.Code]; duplicate {
+			return nil, fmt.Errorf("[stock line %d] %s listed twice", line, chairType.Name)
+		}
+		var level StockLevel
+		// in the order of the columns in the docs, so the same file always gets the same error
+		for _, field := range []struct {
+			name  string
+			count *int
+		}{{"on_hand", &level.OnHand}, {"reserved", &level.Reserved}} {
+			cell := row[columns[field.name]]
+			if *field.count, err = strconv.Atoi(strings.TrimSpace(cell)); err != nil || *field.count < 0 {
+				return nil, fmt.Errorf("[stock line %d] %s is not a count: %q", line, field.name, cell)
+			}
+		}
+		stock[chairType.Code] = level
+	}
+	return stock, nil
+}
+
+func chairTypeByLetterOrName(s string) (ChairType, bool) {
+	for _, chairType := range ChairTypes {
+		if s == string(chairType.Code) || strings.EqualFold(s, chairType.Name) {
+			return chairType, true
+		}
+	}
+	return ChairType{}, false
+}
+
+// ReconciliationLine says how the chairs of a type needed by the plans will be got.
+type ReconciliationLine struct {
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Needed int    `json:"needed"`
+	// Available is what's on hand and not reserved.
+	Available int `json:"available"`
+	FromStock int `json:"from_stock"`
+	Produce   int `json:"produce"`
+}
+
+// Reconciliation has a line for every chair type in the catalog.
+type Reconciliation []ReconciliationLine
+
+// Reconcile splits what the plans need between what can be drawn from stock and what must be produced.
+func Reconcile(needed map[rune]int, stock Stock) Reconciliation {
+	reconciliation := Reconciliation{}
+	for _, chairType := range ChairTypes {
+		level := stock[chairType.Code]
+		line := ReconciliationLine{
+			Type:      string(chairType.Code),
+			Name:      chairType.Name,
+			Needed:    needed[chairType.Code],
+			Available: max(level.OnHand-level.Reserved, 0),
+		}
+		line.FromStock = min(line.Needed, line.Available)
+		line.Produce = line.Needed - line.FromStock
+		reconciliation = append(reconciliation, line)
+	}
+	return reconciliation
+}
+
+// String lists the chair types one per line:
+//
+// W (wooden chair): needed 14, available 28, from stock 14, produce 0
+func (r Reconciliation) String() string {
+	var lines []string
+	for _, line := range r {
+		lines = append(lines, fmt.Sprintf("%s (%s): needed %d, available %d, from stock %d, produce %d",
+			line.Type, line.Name, line.Needed, line.Available, line.FromStock, line.Produce))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,64 @@
+package src
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadStock(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Stock
+		wantErr bool
+	}{
+		{
+			name:  "letters and names, columns in any order",
+			input: "reserved,Type,on_hand\n2,W,10\n0,sofa chair,3\n",
+			want:  Stock{'W': {OnHand: 10, Reserved: 2}, 'S': {OnHand: 3}},
+		},
+		{name: "just the header", input: "type,on_hand,reserved\n", want: Stock{}},
+		{name: "empty", input: "", wantErr: true},
+		{name: "missing column", input: "type,on_hand\nW,1\n", wantErr: true},
+		{name: "unknown chair", input: "type,on_hand,reserved\nX,1,0\n", wantErr: true},
+		{name: "listed twice", input: "type,on_hand,reserved\nW,1,0\nwooden chair,2,0\n", wantErr: true},
+		{name: "not a count", input: "type,on_hand,reserved\nW,many,0\n", wantErr: true},
+		{name: "negative", input: "type,on_hand,reserved\nW,1,-1\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadStock(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadStock() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadStock() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadStock_FirstError(t *testing.T) {
+	// both counts are wrong: on_hand, checked first whatever the order of the columns, is the one reported every time
+	for i := 0; i < 10; i++ {
+		_, err := ReadStock(strings.NewReader("type,reserved,on_hand\nW,-1,many\n"))
+		if want := `[stock line 2] on_hand is not a count: "many"`; err == nil || err.Error() != want {
+			t.Fatalf("ReadStock() error = %v, want %s", err, want)
+		}
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	got := Reconcile(
+		map[rune]int{'W': 5, 'P': 5, 'S': 1},
+		Stock{'W': {OnHand: 10, Reserved: 2}, 'P': {OnHand: 4, Reserved: 1}, 'C': {OnHand: 1, Reserved: 3}},
+	)
+	want := `W (wooden chair): needed 5, available 8, from stock 5, produce 0
+P (plastic chair): needed 5, available 3, from stock 3, produce 2
+S (sofa chair): needed 1, available 0, from stock 0, produce 1
+C (china chair): needed 0, available 0, from stock 0, produce 0`
+	if got.String() != want {
+		t.Errorf("Reconcile() =\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,177 @@
+package src
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+)
+
+// size of a plan cell in the SVG, in pixels.
+// Cells are taller than wide, like the characters they were typed with.
+const (
+	svgCellWidth  = 12
+	svgCellHeight = 20
+)
+
+// svgChairColours tells the chair types apart in the SVG
+var svgChairColours = map[rune]string{
+	'W': "#8b5a2b",
+	'P': "#1e88e5",
+	'S': "#8e24aa",
+	'C': "#00897b",
+}
+
+// RenderSVG draws the plan as an SVG image:
+// the walls, every room filled with a colour of its own, the chairs labelled by type,
+// for every room, its name and chair counts and, for building plans, the name of each floor.
+func (p *Plan) RenderSVG(writer io.Writer) error {
+	w := bufio.NewWriter(writer)
+
+	columns := 0
+	for _, line := range p.Lines {
+		columns = max(columns, len(line))
+	}
+	width, height := columns*svgCellWidth, len(p.Lines)*svgCellHeight
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="monospace">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(w, `<rect width="%d" height="%d" fill="white"/>`+"\n", width, height)
+
+	for i, room := range p.Rooms {
+		fmt.Fprintf(w, `<g class="room" fill="%s">`+"\n", roomColour(i))
+		for _, run := range cellRuns(room.Cells) {
+			fmt.Fprintf(w, `<rect x="%d" y="%d" width="%d" height="%d"/>`+"\n",
+				(run.Column-1)*svgCellWidth, (run.Line-1)*svgCellHeight, run.Length*svgCellWidth, svgCellHeight)
+		}
+		fmt.Fprintln(w, `</g>`)
+	}
+
+	fmt.Fprintln(w, `<g class="walls" stroke="black" stroke-width="2" stroke-linecap="square">`)
+	for l, line := range p.Lines {
+		// a dash in a floor's name is no wall
+		if _, found := floorHeader(line); found {
+			continue
+		}
+		for c, char := range line {
+			p.renderWall(w, Position{Line: l + 1, Column: c + 1}, char)
+		}
+	}
+	fmt.Fprintln(w, `</g>`)
+
+	fmt.Fprintln(w, `<g class="chairs" font-size="12" text-anchor="middle" dominant-baseline="central">`)
+	for _, room := range p.Rooms {
+		for _, chair := range room.Placements {
+			x, y := cellCentre(chair.Position)
+			name := string(chair.Type)
+			if chairType, found := ChairTypeOf(chair.Type); found {
+				name = chairType.Name
+			}
+			fmt.Fprintf(w, `<g><title>%s</title><circle cx="%d" cy="%d" r="%d" fill="%s"/><text x="%d" y="%d" fill="white">%c</text></g>`+"\n",
+				html.EscapeString(name), x, y, svgCellWidth*3/4, svgChairColours[chair.Type], x, y, chair.Type)
+		}
+	}
+	fmt.Fprintln(w, `</g>`)
+
+	fmt.Fprintln(w, `<g class="labels" font-size="12" text-anchor="middle">`)
+	for _, room := range p.Rooms {
+		x, y := cellCentre(roomLabelPosition(room))
+		fmt.Fprintf(w, `<text x="%d" y="%d"><tspan x="%d" font-weight="bold">%s</tspan><tspan x="%d" dy="1.2em" font-size="10">%s</tspan></text>`+"\n",
+			x, y, x, html.EscapeString(room.Name), x, html.EscapeString(ChairCounts(room.Chairs)))
+	}
+	fmt.Fprintln(w, `</g>`)
+
+	if len(p.Floors) > 0 {
+		fmt.Fprintln(w, `<g class="floors" font-size="14" font-weight="bold" dominant-baseline="central">`)
+		for _, floor := range p.Floors {
+			_, y := cellCentre(Position{Line: floor.Line, Column: 1})
+			fmt.Fprintf(w, `<text x="0" y="%d">floor %s</text>`+"\n", y, html.EscapeString(floor.Name))
+		}
+		fmt.Fprintln(w, `</g>`)
+	}
+
+	fmt.Fprintln(w, `</svg>`)
+	return w.Flush()
+}
+
+// renderWall draws the wall drawn with char at pos, if char is a wall at all.
+// A '+' only reaches out to the walls next to it, so corners and junctions come out right.
+func (p *Plan) renderWall(w io.Writer, pos Position, char rune) {
+	x, y := cellCentre(pos)
+	left, top := (pos.Column-1)*svgCellWidth, (pos.Line-1)*svgCellHeight
+	right, bottom := left+svgCellWidth, top+svgCellHeight
+	line := func(x1, y1, x2, y2 int) {
+		fmt.Fprintf(w, `<line x1="%d" y1="%d" x2="%d" y2="%d"/>`+"\n", x1, y1, x2, y2)
+	}
+
+	switch char {
+	case '-':
+		line(left, y, right, y)
+	case '|':
+		line(x, top, x, bottom)
+	case '/':
+		line(left, bottom, right, top)
+	case '\\':
+		line(left, top, right, bottom)
+	case '+':
+		neighbour := func(lines, columns int) rune {
+			return p.At(Position{Line: pos.Line + lines, Column: pos.Column + columns})
+		}
+		if c := neighbour(0, -1); c == '-' || c == '+' {
+			line(left, y, x, y)
+		}
+		if c := neighbour(0, 1); c == '-' || c == '+' {
+			line(x, y, right, y)
+		}
+		if c := neighbour(-1, 0); c == '|' || c == '+' {
+			line(x, top, x, y)
+		}
+		if c := neighbour(1, 0); c == '|' || c == '+' {
+			line(x, y, x, bottom)
+		}
+		if c := neighbour(1, -1); c == '/' {
+			line(x, y, left, bottom)
+		}
+		if c := neighbour(1, 1); c == '\\' {
+			line(x, y, right, bottom)
+		}
+	}
+}
+
+func cellCentre(pos Position) (x, y int) {
+	return (pos.Column-1)*svgCellWidth + svgCellWidth/2, (pos.Line-1)*svgCellHeight + svgCellHeight/2
+}
+
+// roomColour picks a light colour for the i-th room.
+// Consecutive rooms are a golden angle apart on the colour wheel, so neighbours rarely look alike.
+func roomColour(i int) string {
+	return fmt.Sprintf("hsl(%.0f, 70%%, 85%%)", math.Mod(float64(i)*137.508, 360))
+}
+
+// roomLabelPosition is where a room's name and counts go: its title, or the middle of the room if it has none.
+func roomLabelPosition(room *Room) Position {
+	if room.Title.Length > 0 {
+		return Position{Line: room.Title.Line, Column: room.Title.Column + room.Title.Length/2}
+	}
+	runs := cellRuns(room.Cells)
+	if len(runs) == 0 {
+		return Position{}
+	}
+	middle := runs[len(runs)/2]
+	return Position{Line: middle.Line, Column: middle.Column + middle.Length/2}
+}
+
+// cellRuns groups cells that sit next to each other on the same line.
+// The cells must be ordered line by line, left to right, the way Room.Cells are.
+func cellRuns(cells []Position) []Span {
+	var runs []Span
+	for _, cell := range cells {
+		if last := len(runs) - 1; last >= 0 && runs[last].Line == cell.Line &&
+			runs[last].Column+runs[last].Length == cell.Column {
+			runs[last].Length++
+			continue
+		}
+		runs = append(runs, Span{Position: cell, Length: 1})
+	}
+	return runs
+}
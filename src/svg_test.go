@@ -0,0 +1,78 @@
+package src
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestPlan_RenderSVG(t *testing.T) {
+	plan, err := ParsePlan(strings.NewReader(`+-----+-------+
+|(a) W|(b & c)|
+|   W |   P   |
++-----+-------+
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	if err := plan.RenderSVG(&out); err != nil {
+		t.Fatalf("RenderSVG() error = %v", err)
+	}
+	svg := out.String()
+
+	// it has to be well-formed XML, names escaped included
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("RenderSVG() output is not valid XML: %v\n%s", err, svg)
+		}
+	}
+
+	for _, want := range []string{
+		`<g class="room" fill="hsl(0, 70%, 85%)">`,
+		`<g class="room" fill="hsl(138, 70%, 85%)">`,
+		`<title>wooden chair</title>`,
+		`<title>plastic chair</title>`,
+		`>a</tspan>`,
+		`>b &amp; c</tspan>`,
+		`>W: 2, P: 0, S: 0, C: 0</tspan>`,
+		`>W: 0, P: 1, S: 0, C: 0</tspan>`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("RenderSVG() output doesn't contain %s:\n%s", want, svg)
+		}
+	}
+	if got := strings.Count(svg, "<circle"); got != 3 {
+		t.Errorf("RenderSVG() drew %d chairs, want 3", got)
+	}
+	if got := strings.Count(svg, `<g class="room"`); got != 2 {
+		t.Errorf("RenderSVG() filled %d rooms, want 2", got)
+	}
+}
+
+func TestPlan_RenderSVGFloorHeaders(t *testing.T) {
+	walls := func(input string) string {
+		plan, err := ParsePlan(strings.NewReader(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out strings.Builder
+		if err := plan.RenderSVG(&out); err != nil {
+			t.Fatalf("RenderSVG() error = %v", err)
+		}
+		_, walls, _ := strings.Cut(out.String(), `<g class="walls"`)
+		walls, _, _ = strings.Cut(walls, "</g>")
+		return walls
+	}
+
+	withHeader := walls("== floor 2-b ==\n+---+\n| W |\n+---+\n")
+	withoutHeader := walls("\n+---+\n| W |\n+---+\n")
+	if withHeader != withoutHeader {
+		t.Errorf("RenderSVG() walls with a floor header:\n%s\nwant the same as without:\n%s", withHeader, withoutHeader)
+	}
+}
@@ -0,0 +1,56 @@
+package src
+
+import (
+	"errors"
+	"io"
+)
+
+// ParseError is a problem found at a given place of the plan.
+type ParseError struct {
+	Position
+	Message string `json:"message"`
+}
+
+func (e *ParseError) Error() string {
+	return e.Message
+}
+
+// AsParseError finds the ParseError behind err.
+// Errors that don't come from a place in the plan (read errors) become a ParseError with a zero Position.
+func AsParseError(err error) *ParseError {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		return parseErr
+	}
+	return &ParseError{Message: err.Error()}
+}
+
+// Validate parses the whole plan and lists what's wrong with it:
+// the error that stopped the parser, if any, or else the plan's Problems.
+func Validate(reader io.Reader) []*ParseError {
+	plan, err := ParsePlan(reader)
+	if err != nil {
+		return []*ParseError{AsParseError(err)}
+	}
+	return plan.Problems()
+}
+
+// Problems lists what's wrong with a plan that parsed: the rooms that never close,
+// the apartments with a door into another one, and the chairs blocking a door, a gap in a wall,
+// or hemmed in with no free floor next to them.
+func (p *Plan) Problems() []*ParseError {
+	problems := []*ParseError{}
+	for _, room := range p.Unclosed {
+		problem := &ParseError{Message: "room never closes"}
+		if room.Name != "" {
+			problem.Message = "room " + room.Name + " never closes"
+		}
+		if len(room.Cells) > 0 {
+			problem.Position = room.Cells[0]
+		}
+		problems = append(problems, problem)
+	}
+	_, apartmentProblems := p.apartments()
+	problems = append(problems, apartmentProblems...)
+	return append(problems, p.blockedChairs()...)
+}
@@ -0,0 +1,201 @@
+package src
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// viewerPanelWidth is how many columns the side panel of the Viewer takes, the separator included.
+const viewerPanelWidth = 34
+
+// viewerHelp is the Viewer's status line.
+const viewerHelp = "arrows/hjkl: move, tab: next room, q: quit"
+
+// Viewer is the interactive plan viewer: a cursor moving over the cells of the plan,
+// the room under it highlighted, and a side panel telling about the room.
+// It only keeps the state and draws frames; reading the keys and putting the terminal in raw mode is up to the caller.
+type Viewer struct {
+	plan *Plan
+	// Cursor is the cell the cursor is on. It never leaves the drawing.
+	Cursor Position
+	// the longest line of the plan
+	width int
+	// the line and column the view is scrolled to, 0-based
+	top, left int
+}
+
+func NewViewer(plan *Plan) *Viewer {
+	v := &Viewer{plan: plan, Cursor: Position{Line: 1, Column: 1}}
+	for _, line := range plan.Lines {
+		v.width = max(v.width, len(line))
+	}
+	return v
+}
+
+// Move moves the cursor by the given number of lines and columns, stopping at the edges of the drawing.
+func (v *Viewer) Move(lines, columns int) {
+	v.Cursor.Line = min(max(v.Cursor.Line+lines, 1), max(len(v.plan.Lines), 1))
+	v.Cursor.Column = min(max(v.Cursor.Column+columns, 1), max(v.width, 1))
+}
+
+// NextRoom moves the cursor to the next room, in the order of Plan.Rooms: onto its title, or else its first cell.
+func (v *Viewer) NextRoom() {
+	if len(v.plan.Rooms) == 0 {
+		return
+	}
+	next := 0
+	if room, kind := v.plan.RoomAt(v.Cursor); kind == Floor {
+		for i, r := range v.plan.Rooms {
+			if r == room {
+				next = (i + 1) % len(v.plan.Rooms)
+			}
+		}
+	}
+	room := v.plan.Rooms[next]
+	switch {
+	case room.Title.Length > 0:
+		v.Cursor = room.Title.Position
+	case len(room.Cells) > 0:
+		v.Cursor = room.Cells[0]
+	}
+}
+
+// HandleKeys acts on the keys read from a terminal in raw mode: a single read can bring several of them
+// when they're typed fast or pasted. It returns true as soon as one of the keys means quitting.
+// Keys it doesn't know, escape sequences included, are ignored.
+func (v *Viewer) HandleKeys(input string) (quit bool) {
+	for input != "" {
+		key := input[:escapeSequenceLength(input)]
+		input = input[len(key):]
+		if v.handleKey(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeSequenceLength is how many bytes the key at the start of input takes.
+// Arrows come as escape sequences: ESC [ A, or ESC O A in application mode. Other keys, like F5 or Page Up,
+// bring longer ones, ESC [ then parameters up to a final byte from @ to ~: they're taken whole, so they
+// don't turn into keys of their own.
+func escapeSequenceLength(input string) int {
+	if input[0] != '\x1b' || len(input) < 2 {
+		return 1
+	}
+	switch input[1] {
+	case '[':
+		for i := 2; i < len(input); i++ {
+			if input[i] >= '@' && input[i] <= '~' {
+				return i + 1
+			}
+		}
+		return len(input)
+	case 'O':
+		return min(3, len(input))
+	}
+	// ESC then a key is that key with Alt
+	return 2
+}
+
+func (v *Viewer) handleKey(key string) (quit bool) {
+	switch key {
+	case "q", "Q", "\x03":
+		return true
+	case "\x1b[A", "\x1bOA", "k":
+		v.Move(-1, 0)
+	case "\x1b[B", "\x1bOB", "j":
+		v.Move(1, 0)
+	case "\x1b[C", "\x1bOC", "l":
+		v.Move(0, 1)
+	case "\x1b[D", "\x1bOD", "h":
+		v.Move(0, -1)
+	case "\t", "n":
+		v.NextRoom()
+	}
+	return false
+}
+
+// Render draws a frame of the given terminal size on w, the way a terminal in raw mode wants it:
+// the visible part of the plan, scrolled so the cursor stays in sight, the side panel, then the status line.
+func (v *Viewer) Render(w io.Writer, width, height int) error {
+	viewWidth, viewHeight := max(width-viewerPanelWidth, 1), max(height-1, 1)
+	v.scroll(viewWidth, viewHeight)
+
+	room, kind := v.plan.RoomAt(v.Cursor)
+	panel := v.panel(room, kind)
+
+	var frame bytes.Buffer
+	// the frame is drawn over the last one, each line cleared to its end, rather than on a cleared screen:
+	// clearing the whole screen first makes it flicker
+	frame.WriteString("\x1b[H")
+	for row := 0; row < viewHeight; row++ {
+		line := v.top + row + 1
+		for column := v.left + 1; column <= v.left+viewWidth; column++ {
+			pos := Position{Line: line, Column: column}
+			c := v.plan.At(pos)
+			switch cellRoom, _ := v.plan.RoomAt(pos); {
+			case pos == v.Cursor:
+				fmt.Fprintf(&frame, "\x1b[7m%c\x1b[0m", c)
+			case room != nil && cellRoom == room:
+				fmt.Fprintf(&frame, "\x1b[97;44m%c\x1b[0m", c)
+			default:
+				frame.WriteByte(byte(c))
+			}
+		}
+		frame.WriteString(" │")
+		if row < len(panel) && panel[row] != "" {
+			frame.WriteString(" " + panel[row])
+		}
+		frame.WriteString("\x1b[K\r\n")
+	}
+	frame.WriteString(viewerHelp + "\x1b[K\x1b[J")
+
+	_, err := w.Write(frame.Bytes())
+	return err
+}
+
+// scroll moves the view just enough for the cursor to be in it.
+func (v *Viewer) scroll(viewWidth, viewHeight int) {
+	v.top = min(v.top, v.Cursor.Line-1)
+	v.top = max(v.top, v.Cursor.Line-viewHeight)
+	v.left = min(v.left, v.Cursor.Column-1)
+	v.left = max(v.left, v.Cursor.Column-viewWidth)
+}
+
+// panel is what the side panel tells about the cell under the cursor and its room.
+func (v *Viewer) panel(room *Room, kind CellKind) []string {
+	lines := []string{v.Cursor.String(), ""}
+	if kind != Floor {
+		return append(lines, kind.String())
+	}
+
+	name := room.Name
+	if name == "" {
+		name = "(no name)"
+	}
+	lines = append(lines, name, "")
+	for _, chairType := range ChairTypes {
+		lines = append(lines, fmt.Sprintf("%c %-14s %d", chairType.Code, chairType.Name, room.Chairs[chairType.Code]))
+	}
+
+	first, last := room.Cells[0], room.Cells[0]
+	for _, cell := range room.Cells {
+		first.Column = min(first.Column, cell.Column)
+		last.Line = max(last.Line, cell.Line)
+		last.Column = max(last.Column, cell.Column)
+	}
+	lines = append(lines, "",
+		fmt.Sprintf("area: %d cells", len(room.Cells)),
+		fmt.Sprintf("lines %d-%d", first.Line, last.Line),
+		fmt.Sprintf("columns %d-%d", first.Column, last.Column),
+	)
+
+	for i, line := range lines {
+		if len(line) > viewerPanelWidth-3 {
+			lines[i] = strings.TrimSpace(line[:viewerPanelWidth-3])
+		}
+	}
+	return lines
+}
@@ -0,0 +1,130 @@
+package src
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const viewerPlan = `+-----+-------+
+|(a) W|(b)    |
+|   W +---+ P |
++-----+   |   |
+      +---+---+`
+
+func TestViewer_HandleKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     []string
+		want     Position
+		wantQuit bool
+	}{
+		{
+			name: "arrows",
+			keys: []string{"\x1b[B", "\x1b[C", "\x1b[C", "\x1b[A", "\x1b[B", "\x1b[D"},
+			want: Position{Line: 2, Column: 2},
+		},
+		{
+			name: "hjkl",
+			keys: []string{"j", "j", "l", "l", "l", "k", "h"},
+			want: Position{Line: 2, Column: 3},
+		},
+		{
+			name: "stops at the edges",
+			keys: append(strings.Split(strings.Repeat("k", 3)+strings.Repeat("l", 20), ""), "h"),
+			want: Position{Line: 1, Column: 14},
+		},
+		{
+			name: "tab goes from room to room",
+			keys: []string{"\t", "\t"},
+			want: Position{Line: 2, Column: 2},
+		},
+		{
+			name: "tab wraps around",
+			keys: []string{"\t", "\t", "\t", "\t"},
+			want: Position{Line: 4, Column: 8},
+		},
+		{
+			name: "several keys in one read",
+			keys: []string{"jj\x1b[C\x1bOCl"},
+			want: Position{Line: 3, Column: 4},
+		},
+		{
+			name: "escape and unknown sequences are ignored",
+			keys: []string{"\x1b", "j", "\x1b[15~", "\x1b[1;5C", "\x1bx", "\x1b[5~j\x1b"},
+			want: Position{Line: 3, Column: 1},
+		},
+		{
+			name:     "quit",
+			keys:     []string{"j", "q", "j"},
+			want:     Position{Line: 2, Column: 1},
+			wantQuit: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := ParsePlan(strings.NewReader(viewerPlan))
+			if err != nil {
+				t.Fatal(err)
+			}
+			viewer := NewViewer(plan)
+			quit := false
+			for _, key := range tt.keys {
+				if quit = viewer.HandleKeys(key); quit {
+					break
+				}
+			}
+			if viewer.Cursor != tt.want {
+				t.Errorf("cursor at %v, want %v", viewer.Cursor, tt.want)
+			}
+			if quit != tt.wantQuit {
+				t.Errorf("quit = %v, want %v", quit, tt.wantQuit)
+			}
+		})
+	}
+}
+
+func TestViewer_Render(t *testing.T) {
+	plan, err := ParsePlan(strings.NewReader(viewerPlan))
+	if err != nil {
+		t.Fatal(err)
+	}
+	viewer := NewViewer(plan)
+	viewer.Cursor = Position{Line: 3, Column: 13}
+
+	var frame strings.Builder
+	// 10 columns of plan: the view has to scroll to the cursor
+	if err := viewer.Render(&frame, viewerPanelWidth+10, 14); err != nil {
+		t.Fatal(err)
+	}
+
+	if highlighted := strings.Count(frame.String(), "\x1b[97;44m"); highlighted != 9 {
+		t.Errorf("%d cells highlighted, want the 9 of room b in sight, the cursor aside", highlighted)
+	}
+	if !strings.Contains(frame.String(), "\x1b[7mP\x1b[0m") {
+		t.Error("the cursor isn't drawn")
+	}
+	if strings.Contains(frame.String(), "\x1b[2J") {
+		t.Error("the frame clears the screen, which flickers")
+	}
+
+	want := `---+------ │ line 3, column 13
+) W|(b)    │
+ W +---+ P │ b
+---+   |   │
+   +---+-- │ W wooden chair   0
+           │ P plastic chair  1
+           │ S sofa chair     0
+           │ C china chair    0
+           │
+           │ area: 13 cells
+           │ lines 2-4
+           │ columns 8-14
+           │
+arrows/hjkl: move, tab: next room, q: quit`
+	got := regexp.MustCompile("\x1b\\[[0-9;]*[A-Za-z]").ReplaceAllString(frame.String(), "")
+	if got = strings.ReplaceAll(got, "\r\n", "\n"); got != want {
+		t.Errorf("frame:\n%s\nwant:\n%s", got, want)
+	}
+}